@@ -2,11 +2,14 @@ module github.com/Chrisser1/Discord-Bot-DTU
 
 go 1.23.3
 
-require github.com/bwmarrin/discordgo v0.28.1
+require (
+	github.com/bwmarrin/discordgo v0.28.1
+	github.com/chromedp/cdproto v0.0.0-20250222051814-50c6cb17f10a
+	golang.org/x/image v0.24.0
+)
 
 require (
 	github.com/andybalholm/cascadia v1.3.3 // indirect
-	github.com/chromedp/cdproto v0.0.0-20250222051814-50c6cb17f10a // indirect
 	github.com/chromedp/sysutil v1.1.0 // indirect
 	github.com/go-json-experiment/json v0.0.0-20250211171154-1ae217ad3535 // indirect
 	github.com/gobwas/httphead v0.1.0 // indirect
@@ -3,23 +3,102 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// DefaultCatalogueBaseURL is the public DTU course catalogue.
+const DefaultCatalogueBaseURL = "https://kurser.dtu.dk"
+
 type Config struct {
 	BotToken       string
 	UniqueServerID string
+	// CourseSource selects how course pages are fetched: "chromedp" or "http".
+	// The other source is used as a fallback if the selected one fails.
+	CourseSource string
+	// CatalogueBaseURL is the root of the course catalogue, e.g. "https://kurser.dtu.dk".
+	// It can point at a local server serving recorded course pages.
+	CatalogueBaseURL string
+	// BrowserTabs is the number of pages the shared headless browser renders concurrently.
+	BrowserTabs int
+	// ChromeRemoteURL is the DevTools URL of an already running Chrome, e.g. "ws://chrome:9222".
+	// When set, no local browser is launched and the options below are ignored.
+	ChromeRemoteURL string
+	// ChromePath is the Chrome executable to launch. Empty means search the usual locations.
+	ChromePath string
+	// ChromeFlags are extra command line flags such as "no-sandbox" or "proxy-server=http://proxy:3128".
+	ChromeFlags []string
+	// ChromeUserAgent overrides the browser's user-agent when set.
+	ChromeUserAgent string
+	// CourseCacheDir is where fetched courses are cached on disk.
+	CourseCacheDir string
+	// CourseCacheTTL is how long a cached course is considered fresh.
+	// Older entries are still served, but refreshed in the background.
+	CourseCacheTTL time.Duration
+	// AcademicCalendarFile is the JSON file with the dates of semesters, 3-week periods and exam periods.
+	AcademicCalendarFile string
+	// SubscriptionsFile is where the course subscriptions of all users are stored.
+	SubscriptionsFile string
+	// SnapshotsDir holds the last seen version of every subscribed course.
+	SnapshotsDir string
+	// SubscriptionCheckInterval is how often subscribed courses are re-fetched to look for changes.
+	// Zero disables the checks.
+	SubscriptionCheckInterval time.Duration
+	// SchedulesFile is where the personal schedules of all users are stored.
+	SchedulesFile string
+	// CourseIndexFile is the course store: the metadata of every fetched or crawled course, one JSON object per line.
+	CourseIndexFile string
+	// CatalogueListURL is the catalogue page listing all courses. Empty uses the catalogue's search page.
+	CatalogueListURL string
+	// CrawlInterval is how often the bot crawls the whole catalogue. Zero disables scheduled crawls.
+	CrawlInterval time.Duration
+	// CrawlConcurrency is the number of courses the crawler fetches at once.
+	CrawlConcurrency int
+	// CrawlDelay is the minimum time between two course fetches of the crawler.
+	CrawlDelay time.Duration
 }
 
 var GlobalConfig *Config
 
 func LoadConfig() *Config {
 	GlobalConfig = &Config{
-		BotToken:       getEnv("BOT_TOKEN", ""),
-		UniqueServerID: getEnv("UNIQUE_SERVER_ID", ""),
+		BotToken:                  getOptionalEnv("BOT_TOKEN"),
+		UniqueServerID:            getOptionalEnv("UNIQUE_SERVER_ID"),
+		CourseSource:              getEnv("COURSE_SOURCE", "chromedp"),
+		CatalogueBaseURL:          strings.TrimSuffix(getEnv("COURSE_CATALOGUE_URL", DefaultCatalogueBaseURL), "/"),
+		BrowserTabs:               getEnvInt("BROWSER_TABS", 2),
+		ChromeRemoteURL:           getOptionalEnv("CHROME_REMOTE_URL"),
+		ChromePath:                getOptionalEnv("CHROME_PATH"),
+		ChromeFlags:               getEnvList("CHROME_FLAGS"),
+		ChromeUserAgent:           getOptionalEnv("CHROME_USER_AGENT"),
+		CourseCacheDir:            getEnv("COURSE_CACHE_DIR", "data/cache"),
+		CourseCacheTTL:            getEnvDuration("COURSE_CACHE_TTL", 24*time.Hour),
+		AcademicCalendarFile:      getEnv("ACADEMIC_CALENDAR_FILE", "data/academic_calendar.json"),
+		SubscriptionsFile:         getEnv("SUBSCRIPTIONS_FILE", "data/subscriptions.json"),
+		SnapshotsDir:              getEnv("SNAPSHOTS_DIR", "data/snapshots"),
+		SubscriptionCheckInterval: getEnvDuration("SUBSCRIPTION_CHECK_INTERVAL", 6*time.Hour),
+		SchedulesFile:             getEnv("SCHEDULES_FILE", "data/schedules.json"),
+		CourseIndexFile:           getEnv("COURSE_INDEX_FILE", "data/course_index.jsonl"),
+		CatalogueListURL:          getOptionalEnv("COURSE_CATALOGUE_LIST_URL"),
+		CrawlInterval:             getEnvDuration("CRAWL_INTERVAL", 0),
+		CrawlConcurrency:          getEnvInt("CRAWL_CONCURRENCY", 2),
+		CrawlDelay:                getEnvDuration("CRAWL_DELAY", time.Second),
 	}
 	return GlobalConfig
 }
 
+// RequireBotSettings stops the program if the settings needed to run the Discord bot are missing.
+// Other commands, such as the crawler, can run without them.
+func (c *Config) RequireBotSettings() {
+	if c.BotToken == "" {
+		log.Fatal("Environment variable BOT_TOKEN not set and no fallback provided")
+	}
+	if c.UniqueServerID == "" {
+		log.Fatal("Environment variable UNIQUE_SERVER_ID not set and no fallback provided")
+	}
+}
+
 func getEnv(key, fallback string) string {
 	value := os.Getenv(key)
 	if value != "" {
@@ -30,3 +109,37 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value := getEnv(key, strconv.Itoa(fallback))
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be a number, got %q", key, value)
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := getEnv(key, fallback.String())
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Environment variable %s must be a duration such as \"6h\", got %q", key, value)
+	}
+	return d
+}
+
+// getOptionalEnv returns the value of the environment variable, or an empty string if it is not set.
+func getOptionalEnv(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
+
+// getEnvList splits a comma-separated environment variable into its non-empty items.
+func getEnvList(key string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -34,7 +34,7 @@ func handlePaginationButton(s *discordgo.Session, i *discordgo.InteractionCreate
 	}
 
 	// (Optional) If you only want the original author to page through, check:
-	if i.Member.User.ID != pd.AuthorID {
+	if utils.InteractionUserID(i) != pd.AuthorID {
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -0,0 +1,40 @@
+package discord
+
+import (
+	"log"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+)
+
+// courseStoreReloadInterval is how often the course store file is checked for changes
+// written by other processes, such as a crawl started from the command line.
+const courseStoreReloadInterval = time.Minute
+
+// loadCourseStore loads the course store up front, so the first autocompletion does not wait for it.
+func loadCourseStore() (*model.CourseStore, error) {
+	store, err := model.GetCourseStore()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded %d courses for autocompletion", store.Len())
+	return store, nil
+}
+
+// watchCourseStore merges outside changes of the course store into memory every interval
+// until the service is closed.
+func (s *Service) watchCourseStore(store *model.CourseStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := store.Reload(); err != nil {
+				log.Println("Error reloading the course store:", err)
+			}
+		case <-s.stopChan:
+			return
+		}
+	}
+}
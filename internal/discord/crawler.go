@@ -0,0 +1,47 @@
+package discord
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+)
+
+// crawlCatalogue crawls the whole course catalogue at start and then every interval until the
+// service is closed. A crawl in progress is stopped when the service closes, keeping what it has fetched so far.
+func (s *Service) crawlCatalogue(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.stopChan
+		cancel()
+	}()
+
+	// Crawl once right away, so a fresh start does not wait a whole interval for the index.
+	s.runCrawl(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runCrawl(ctx)
+		case <-ctx.Done():
+			// Service closed; exit the crawl loop.
+			return
+		}
+	}
+}
+
+// runCrawl runs one crawl and logs how it went.
+func (s *Service) runCrawl(ctx context.Context) {
+	result, err := model.RunCrawl(ctx)
+	if err != nil {
+		log.Printf("[Crawler] %v", err)
+		return
+	}
+	log.Printf("[Crawler] indexed %d of %d courses in %s, %d failed; course fetches so far: %s",
+		result.Fetched, result.Listed, result.Duration.Round(time.Second), len(result.Failed), model.GetFetchMetrics())
+}
@@ -2,7 +2,9 @@ package discord
 
 import (
 	"log"
+	"strings"
 
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
 
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
@@ -13,7 +15,9 @@ import (
 type Service struct {
 	session            *discordgo.Session
 	paginationManager  *utils.PaginatedSessions
+	browserPool        *utils.BrowserPool
 	registeredCommands []*discordgo.ApplicationCommand
+	stopChan           chan struct{} // channel to signal background loops to stop
 }
 
 func New(token string, pm *utils.PaginatedSessions) *Service {
@@ -22,9 +26,20 @@ func New(token string, pm *utils.PaginatedSessions) *Service {
 		log.Fatal("error creating Discord session,", err)
 	}
 
+	// One long-lived browser is shared by all course fetches.
+	browserPool := utils.NewBrowserPool(config.GlobalConfig.BrowserTabs, utils.BrowserOptions{
+		RemoteURL: config.GlobalConfig.ChromeRemoteURL,
+		ExecPath:  config.GlobalConfig.ChromePath,
+		Flags:     config.GlobalConfig.ChromeFlags,
+		UserAgent: config.GlobalConfig.ChromeUserAgent,
+	})
+	model.SetCourseSource(model.NewConfiguredCourseSource(browserPool))
+
 	return &Service{
 		session:           session,
 		paginationManager: pm,
+		browserPool:       browserPool,
+		stopChan:          make(chan struct{}),
 	}
 }
 
@@ -44,7 +59,12 @@ func (s *Service) AddCommandHandlers() {
 			}
 
 		case discordgo.InteractionMessageComponent:
-			handlePaginationButton(sess, i, s.paginationManager)
+			customID := i.MessageComponentData().CustomID
+			if strings.HasPrefix(customID, "page_") {
+				handlePaginationButton(sess, i, s.paginationManager)
+			} else if h, ok := handlers.FindComponentHandler(customID); ok {
+				h(sess, i, s.paginationManager)
+			}
 
 		default:
 			log.Panicf("unexpected discordgo.InteractionType: %#v", i.Interaction.Type)
@@ -52,8 +72,37 @@ func (s *Service) AddCommandHandlers() {
 	})
 }
 
+// Start opens the Discord session and starts the background jobs: reloading the course store,
+// watching subscribed courses for changes and, if configured, crawling the course catalogue.
 func (s *Service) Start() error {
-	return s.session.Open()
+	if err := s.session.Open(); err != nil {
+		return err
+	}
+	if store, err := loadCourseStore(); err != nil {
+		log.Println("Error loading the course store:", err)
+	} else {
+		go s.watchCourseStore(store, courseStoreReloadInterval)
+	}
+	if interval := config.GlobalConfig.SubscriptionCheckInterval; interval > 0 {
+		go s.watchSubscriptions(interval)
+	}
+	if interval := config.GlobalConfig.CrawlInterval; interval > 0 {
+		go s.crawlCatalogue(interval)
+	}
+	return nil
+}
+
+// Close stops the background loops and shuts down the shared browser and the Discord session.
+func (s *Service) Close() {
+	close(s.stopChan)
+	s.browserPool.Close()
+	if err := model.SaveReferenceIndex(); err != nil {
+		log.Println("Error saving the reference index:", err)
+	}
+	log.Println("Course fetches since startup:", model.GetFetchMetrics())
+	if err := s.session.Close(); err != nil {
+		log.Println("Error closing Discord session:", err)
+	}
 }
 
 func (s *Service) RegisterCommands() []*discordgo.ApplicationCommand {
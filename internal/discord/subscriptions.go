@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxNotificationLength keeps change notifications below Discord's limit of 6000 characters per embed.
+const maxNotificationLength = 5000
+
+// watchSubscriptions re-fetches the subscribed courses every interval until the service is closed.
+func (s *Service) watchSubscriptions(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkSubscriptions()
+		case <-s.stopChan:
+			// Service closed; exit the subscription loop.
+			return
+		}
+	}
+}
+
+// checkSubscriptions compares the current version of every subscribed course with its
+// snapshot, and notifies the subscribers of any changes.
+func (s *Service) checkSubscriptions() {
+	store, err := model.GetSubscriptionStore()
+	if err != nil {
+		log.Println("Error loading subscriptions:", err)
+		return
+	}
+
+	for _, courseNumber := range store.Courses() {
+		select {
+		case <-s.stopChan:
+			return
+		default:
+		}
+
+		course, err := model.RefreshCourse(courseNumber)
+		if err != nil {
+			log.Printf("Error refreshing subscribed course: %v", err)
+			continue
+		}
+
+		changes, err := store.UpdateSnapshot(course)
+		if err != nil {
+			log.Println("Error saving course snapshot:", err)
+		}
+		if len(changes) > 0 {
+			s.notifySubscribers(course, changes, store.Subscribers(courseNumber))
+		}
+	}
+}
+
+// notifySubscribers sends the changes to every subscriber, by direct message or in their channel.
+func (s *Service) notifySubscribers(course *model.Course, changes []model.FieldChange, subs []model.Subscription) {
+	embed := changesEmbed(course, changes)
+
+	for _, sub := range subs {
+		if sub.ChannelID != "" {
+			_, err := s.session.ChannelMessageSendComplex(sub.ChannelID, &discordgo.MessageSend{
+				Content:         fmt.Sprintf("<@%s>", sub.UserID),
+				Embeds:          []*discordgo.MessageEmbed{embed},
+				AllowedMentions: &discordgo.MessageAllowedMentions{Users: []string{sub.UserID}},
+			})
+			if err != nil {
+				log.Printf("Failed to post change notification for %s in channel %s: %v", course.CourseNumber, sub.ChannelID, err)
+			}
+			continue
+		}
+
+		channel, err := s.session.UserChannelCreate(sub.UserID)
+		if err != nil {
+			log.Printf("Failed to open direct message with user %s: %v", sub.UserID, err)
+			continue
+		}
+		if _, err := s.session.ChannelMessageSendEmbed(channel.ID, embed); err != nil {
+			log.Printf("Failed to send change notification for %s to user %s: %v", course.CourseNumber, sub.UserID, err)
+		}
+	}
+}
+
+// changesEmbed shows the changes to a course as one field per change, in the style of /course_diff.
+func changesEmbed(course *model.Course, changes []model.FieldChange) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Course updated: %s - %s", course.CourseNumber, course.Title),
+		URL:         model.CourseURL(course.CourseNumber, course.AcademicYear),
+		Description: fmt.Sprintf("%d field(s) changed since the last check.", len(changes)),
+		Color:       0x606060,
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Use /unsubscribe %s to stop these notifications", course.CourseNumber)},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	length := len(embed.Title) + len(embed.Description) + len(embed.Footer.Text)
+	for idx, change := range changes {
+		field := &discordgo.MessageEmbedField{Name: change.GetSectionName(), Value: change.GetSectionValue()}
+		// Discord allows at most 25 fields and 6000 characters per embed.
+		if idx == 25 || length+len(field.Name)+len(field.Value) > maxNotificationLength {
+			embed.Description += fmt.Sprintf(" %d more not shown.", len(changes)-idx)
+			break
+		}
+		length += len(field.Name) + len(field.Value)
+		embed.Fields = append(embed.Fields, field)
+	}
+	return embed
+}
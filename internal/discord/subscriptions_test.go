@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+)
+
+func TestChangesEmbed(t *testing.T) {
+	course := &model.Course{CourseNumber: "02105", Title: "Algorithms 1", AcademicYear: "2025-2026"}
+	change := func(idx int, value string) model.FieldChange {
+		return model.FieldChange{Section: "Section", Field: fmt.Sprintf("Field %d", idx), Old: "old", New: value, OldLabel: "Before", NewLabel: "Now"}
+	}
+
+	tests := []struct {
+		name       string
+		changes    []model.FieldChange
+		wantFields int
+		wantMore   string
+	}{
+		{"one change", []model.FieldChange{change(0, "new")}, 1, ""},
+		{"at most 25 fields", repeatChanges(30, func(idx int) model.FieldChange { return change(idx, "new") }), 25, "5 more not shown."},
+		{"bounded length", repeatChanges(20, func(idx int) model.FieldChange { return change(idx, strings.Repeat("x", 1000)) }), 9, "11 more not shown."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embed := changesEmbed(course, tt.changes)
+			if embed.Title != "Course updated: 02105 - Algorithms 1" {
+				t.Errorf("Title = %q", embed.Title)
+			}
+			if !strings.Contains(embed.Footer.Text, "/unsubscribe 02105") {
+				t.Errorf("Footer = %q, want it to explain how to unsubscribe", embed.Footer.Text)
+			}
+			if len(embed.Fields) != tt.wantFields {
+				t.Errorf("%d fields, want %d", len(embed.Fields), tt.wantFields)
+			}
+			if tt.wantMore != "" && !strings.HasSuffix(embed.Description, tt.wantMore) {
+				t.Errorf("Description = %q, want it to end in %q", embed.Description, tt.wantMore)
+			}
+			if !strings.HasPrefix(embed.Fields[0].Value, "**Before:** old\n**Now:** ") {
+				t.Errorf("first field = %q, want the old and new value", embed.Fields[0].Value)
+			}
+		})
+	}
+}
+
+func repeatChanges(n int, change func(int) model.FieldChange) []model.FieldChange {
+	changes := make([]model.FieldChange, n)
+	for idx := range changes {
+		changes[idx] = change(idx)
+	}
+	return changes
+}
@@ -2,48 +2,42 @@ package autocompletions
 
 import (
 	"log"
-	"strings"
 
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+// Discord allows at most 25 autocomplete choices, with names of at most 100 characters.
+const (
+	maxAutocompleteChoices = 25
+	maxChoiceNameLength    = 100
+)
+
+// CourseAutocomplete suggests known courses for whichever course option the user is typing in.
+// It works for any command, including options nested in subcommands.
 func CourseAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// Sanity check: ensure we have the right command & option
-	data := i.ApplicationCommandData()
-	if data.Name != "fetch_course" {
+	// Sanity check: ensure we have an option to complete
+	focused := utils.FocusedOption(i.ApplicationCommandData().Options)
+	if focused == nil {
 		return
 	}
-	if len(data.Options) == 0 {
-		return
-	}
-
-	// Get user input
-	userInput := strings.ToUpper(data.Options[0].StringValue())
 
-	// Get saved course numbers
-	courseNumbers, err := model.GetSavedCourses()
+	// Get known courses
+	store, err := model.GetCourseStore()
 	if err != nil {
-		log.Println("Error fetching saved courses: ", err)
+		log.Println("Error loading the course store:", err)
 		return
 	}
 
-	// Filter matching courses
+	// Rank the courses by how well their number or titles match what the user has typed so far
 	var choices []*discordgo.ApplicationCommandOptionChoice
-	for _, course := range courseNumbers {
-		if strings.HasPrefix(course, userInput) {
-			// Get the course number by it self
-			courseNumber := strings.Split(course, ",")[0]
-
-			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
-				Name:  course,
-				Value: courseNumber,
-			})
-		}
-		if len(choices) >= 25 { // Discord limits autocomplete results to 25
-			break
-		}
+	for _, course := range store.Search(focused.StringValue(), maxAutocompleteChoices) {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  utils.Truncate(course.String(), maxChoiceNameLength),
+			Value: course.Number,
+		})
 	}
 
 	// Respond with autocomplete choices
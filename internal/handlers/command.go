@@ -1,12 +1,41 @@
 package handlers
 
 import (
+	"log"
+	"strings"
+
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/handlers/autocompletions"
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/handlers/commands"
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
 	"github.com/bwmarrin/discordgo"
 )
 
+// CommandHandler handles a single application command interaction.
+type CommandHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions)
+
+// Deferred wraps a handler that may take longer than Discord's 3 second deadline.
+// The interaction is acknowledged with a "thinking" state before the handler runs,
+// so the handler must answer with utils.EditResponse or utils.SendFollowUp
+// instead of InteractionRespond.
+func Deferred(h CommandHandler) CommandHandler {
+	return deferred(h, false)
+}
+
+// DeferredEphemeral is like Deferred, but only the invoking user sees the response.
+func DeferredEphemeral(h CommandHandler) CommandHandler {
+	return deferred(h, true)
+}
+
+func deferred(h CommandHandler, ephemeral bool) CommandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+		if err := utils.DeferResponse(s, i, ephemeral); err != nil {
+			log.Println("Failed to defer interaction response:", err)
+			return
+		}
+		h(s, i, pm)
+	}
+}
+
 var (
 	Commands = []*discordgo.ApplicationCommand{
 		{
@@ -14,21 +43,204 @@ var (
 			Description: "Fetches a specific dtu course",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "course_code",
-					Description: "The course code to fetch",
-					Required:    true,
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "course_code",
+					Description:  "The course code to fetch",
+					Required:     true,
 					Autocomplete: true,
 				},
+				academicYearOption("year", "The academic year, e.g. 2025-2026 (defaults to the current one)", false),
+			},
+		},
+		{
+			Name:        "schedule",
+			Description: "Keep track of your personal DTU schedule",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "add",
+					Description: "Add a course to your schedule",
+					Options: []*discordgo.ApplicationCommandOption{
+						courseCodeOption("The course code to add"),
+						semesterOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "remove",
+					Description: "Remove a course from your schedule",
+					Options: []*discordgo.ApplicationCommandOption{
+						courseCodeOption("The course code to remove"),
+						semesterOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "show",
+					Description: "Show your weekly timetable",
+					Options: []*discordgo.ApplicationCommandOption{
+						semesterOption(),
+					},
+				},
+			},
+		},
+		{
+			Name:        "check_conflicts",
+			Description: "Checks courses for overlapping schedule blocks and exams",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "courses",
+					Description: "Course codes separated by spaces or commas (defaults to your saved schedule)",
+					Required:    false,
+				},
+				semesterOption(),
+			},
+		},
+		{
+			Name:        "export_calendar",
+			Description: "Exports the schedule and exams of courses as an .ics calendar file",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "courses",
+					Description: "Course codes separated by spaces or commas (defaults to your saved schedule)",
+					Required:    false,
+				},
+				semesterOption(),
+			},
+		},
+		{
+			Name:        "course_diff",
+			Description: "Shows what changed in a course between two academic years",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to compare"),
+				academicYearOption("year_a", "The earlier academic year, e.g. 2024-2025", true),
+				academicYearOption("year_b", "The later academic year, e.g. 2025-2026", true),
+			},
+		},
+		{
+			Name:        "subscribe",
+			Description: "Get notified when a course page changes",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to follow"),
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "notify_in",
+					Description: "Where to send notifications (defaults to a direct message)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Direct message", Value: "dm"},
+						{Name: "This channel", Value: "channel"},
+					},
+				},
+			},
+		},
+		{
+			Name:        "unsubscribe",
+			Description: "Stop notifications about a course",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to stop following"),
+			},
+		},
+		{
+			Name:        "prereq_tree",
+			Description: "Shows the prerequisites of a course, and theirs, as a tree",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to show the prerequisites of"),
+				academicYearOption("year", "The academic year, e.g. 2025-2026 (defaults to the current one)", false),
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "dot",
+					Description: "Also attach the graph as a Graphviz DOT file",
+					Required:    false,
+				},
 			},
 		},
+		{
+			Name:        "unlocks",
+			Description: "Lists the courses that have a course as a prerequisite",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to look up"),
+			},
+		},
+		{
+			Name:        "mutually_exclusive",
+			Description: "Lists the courses that cannot be taken together with a course",
+			Options: []*discordgo.ApplicationCommandOption{
+				courseCodeOption("The course code to look up"),
+			},
+		},
+	}
+
+	CommandHandlers = map[string]CommandHandler{
+		"fetch_course":       Deferred(commands.FetchCourse),
+		"schedule":           DeferredEphemeral(commands.Schedule),
+		"check_conflicts":    Deferred(commands.CheckConflicts),
+		"export_calendar":    DeferredEphemeral(commands.ExportCalendar),
+		"course_diff":        Deferred(commands.CourseDiff),
+		"subscribe":          DeferredEphemeral(commands.Subscribe),
+		"unsubscribe":        DeferredEphemeral(commands.Unsubscribe),
+		"prereq_tree":        Deferred(commands.PrereqTree),
+		"unlocks":            Deferred(commands.Unlocks),
+		"mutually_exclusive": Deferred(commands.MutuallyExclusive),
 	}
 
-	CommandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions){
-		"fetch_course":        commands.FetchCourse,
+	// ComponentHandlers handle message components such as buttons.
+	// The key is the prefix of the component's custom ID, followed by "_".
+	ComponentHandlers = map[string]CommandHandler{
+		commands.RetryFetchCoursePrefix: Deferred(commands.RetryFetchCourse),
 	}
 
 	AutocompleteHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"fetch_course": autocompletions.CourseAutocomplete,
+		"fetch_course":       autocompletions.CourseAutocomplete,
+		"schedule":           autocompletions.CourseAutocomplete,
+		"course_diff":        autocompletions.CourseAutocomplete,
+		"subscribe":          autocompletions.CourseAutocomplete,
+		"unsubscribe":        autocompletions.CourseAutocomplete,
+		"prereq_tree":        autocompletions.CourseAutocomplete,
+		"unlocks":            autocompletions.CourseAutocomplete,
+		"mutually_exclusive": autocompletions.CourseAutocomplete,
 	}
 )
+
+// FindComponentHandler returns the handler whose prefix matches the custom ID.
+func FindComponentHandler(customID string) (CommandHandler, bool) {
+	for prefix, h := range ComponentHandlers {
+		if strings.HasPrefix(customID, prefix+"_") {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// courseCodeOption is a required course code option with course autocomplete.
+func courseCodeOption(description string) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionString,
+		Name:         "course_code",
+		Description:  description,
+		Required:     true,
+		Autocomplete: true,
+	}
+}
+
+// semesterOption is an optional semester such as "Autumn 2025" or "F26".
+func semesterOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "semester",
+		Description: "The semester, e.g. \"Autumn 2025\" or \"F26\" (defaults to the current one)",
+		Required:    false,
+	}
+}
+
+// academicYearOption is an academic year option such as "2025-2026".
+func academicYearOption(name, description string, required bool) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        name,
+		Description: description,
+		Required:    required,
+	}
+}
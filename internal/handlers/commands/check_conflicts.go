@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxConflictCourses bounds how many courses are fetched for a single conflict check.
+const maxConflictCourses = 10
+
+// courseListSeparator splits a list of course codes such as "02105, 01017 02101".
+var courseListSeparator = regexp.MustCompile(`[\s,;]+`)
+
+// CheckConflicts handles /check_conflicts. It compares the given courses, or the user's
+// saved schedule if none are given, and reports overlapping schedule blocks and exams.
+func CheckConflicts(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+
+	semester, err := semesterFromOptions(options)
+	if err != nil {
+		editContent(s, i, err.Error())
+		return
+	}
+
+	var courseIDs []string
+	if opt, ok := options["courses"]; ok {
+		courseIDs = parseCourseList(opt.StringValue())
+	}
+	if len(courseIDs) == 0 {
+		store, err := model.GetScheduleStore()
+		if err != nil {
+			log.Println("Error loading schedules:", err)
+			editContent(s, i, "Your schedule could not be loaded right now.")
+			return
+		}
+		courseIDs = store.Courses(utils.InteractionUserID(i), semester)
+	}
+
+	if len(courseIDs) < 2 {
+		editContent(s, i, fmt.Sprintf("Give at least two course codes, or add them to your %s schedule with `/schedule add`.", semester))
+		return
+	}
+	if len(courseIDs) > maxConflictCourses {
+		editContent(s, i, fmt.Sprintf("At most %d courses can be checked at once.", maxConflictCourses))
+		return
+	}
+	for _, courseID := range courseIDs {
+		if err := model.ValidateCourseNumber(courseID); err != nil {
+			respondWithFetchError(s, i, courseID, err)
+			return
+		}
+	}
+
+	courses, failed := fetchCourses(courseIDs, semester.AcademicYear())
+	conflicts := model.FindConflicts(courses, semester.Semester)
+
+	var fields []*discordgo.MessageEmbedField
+	for _, conflict := range conflicts {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s × %s", conflictIcon(conflict.Kind), conflict.A.CourseNumber, conflict.B.CourseNumber),
+			Value: utils.Truncate("> "+strings.Join(conflict.Reasons, "\n> "), utils.MaxFieldValueLength),
+		})
+	}
+	if len(failed) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Could not be fetched",
+			Value: strings.Join(failed, ", "),
+		})
+	}
+	// Discord allows at most 25 fields per embed.
+	if len(fields) > 25 {
+		fields = fields[:25]
+	}
+
+	description := fmt.Sprintf("No schedule or exam conflicts between %s.", courseNumbers(courses))
+	color := 0x2e8b57
+	if len(conflicts) > 0 {
+		description = fmt.Sprintf("Found %d conflict(s) between %s.", len(conflicts), courseNumbers(courses))
+		color = 0xcc3333
+	}
+
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "Schedule conflicts",
+		Description: description,
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}}
+	if err := utils.EditResponse(s, i, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+		log.Println("Failed to respond with conflicts embed:", err)
+	}
+}
+
+// parseCourseList splits user input into distinct, upper-case course codes.
+func parseCourseList(input string) []string {
+	var courseIDs []string
+	seen := make(map[string]bool)
+	for _, courseID := range courseListSeparator.Split(strings.ToUpper(input), -1) {
+		if courseID != "" && !seen[courseID] {
+			seen[courseID] = true
+			courseIDs = append(courseIDs, courseID)
+		}
+	}
+	return courseIDs
+}
+
+// courseNumbers joins the course numbers of the courses, e.g. "02105, 01017".
+func courseNumbers(courses []*model.Course) string {
+	numbers := make([]string, len(courses))
+	for idx, course := range courses {
+		numbers[idx] = course.CourseNumber
+	}
+	return strings.Join(numbers, ", ")
+}
+
+func conflictIcon(kind model.ConflictKind) string {
+	if kind == model.ConflictExam {
+		return "📝 Exam:"
+	}
+	return "🗓️ Schedule:"
+}
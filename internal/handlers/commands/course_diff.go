@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// CourseDiff handles /course_diff. It fetches the course in two academic years and shows
+// every field that changed, with changes to e.g. the exam form or ECTS highlighted first.
+func CourseDiff(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := strings.ToUpper(strings.TrimSpace(options["course_code"].StringValue()))
+	yearA := strings.TrimSpace(options["year_a"].StringValue())
+	yearB := strings.TrimSpace(options["year_b"].StringValue())
+
+	if yearA == yearB {
+		editContent(s, i, "Give two different academic years to compare, e.g. `2024-2025` and `2025-2026`.")
+		return
+	}
+
+	// Both versions are fetched at once, since each may need to render a page.
+	var courseA, courseB *model.Course
+	var errA, errB error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		courseA, errA = model.FetchCourseForYear(courseID, yearA)
+	}()
+	go func() {
+		defer wg.Done()
+		courseB, errB = model.FetchCourseForYear(courseID, yearB)
+	}()
+	wg.Wait()
+
+	for _, err := range []error{errA, errB} {
+		if err != nil {
+			log.Printf("Error fetching course: %v", err)
+			respondWithFetchError(s, i, courseID, err)
+			return
+		}
+	}
+
+	changes := model.DiffCourses(courseA, courseB, yearA, yearB)
+	title := fmt.Sprintf("Changes to %s - %s: %s → %s", courseB.CourseNumber, courseB.Title, yearA, yearB)
+	if len(changes) == 0 {
+		embeds := []*discordgo.MessageEmbed{{
+			Title:       title,
+			Description: "No differences between the two versions.",
+			Color:       0x606060,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		}}
+		if err := utils.EditResponse(s, i, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+			log.Println("Failed to respond with course diff embed:", err)
+		}
+		return
+	}
+
+	fields := make([]utils.Section, 0, len(changes))
+	var highlighted []string
+	for _, change := range changes {
+		fields = append(fields, change)
+		if change.Important {
+			highlighted = append(highlighted, change.Field)
+		}
+	}
+
+	description := fmt.Sprintf("%d field(s) changed.", len(changes))
+	if len(highlighted) > 0 {
+		description += fmt.Sprintf(" ⚠️ Check the changes to: **%s**", strings.Join(highlighted, ", "))
+	}
+
+	paginationID := utils.BuildPaginationID()
+	data := &utils.PaginationData{
+		Fields:      fields,
+		PageIndex:   0,
+		Description: description,
+		AuthorID:    utils.InteractionUserID(i),
+		Title:       title,
+		Footer:      fmt.Sprintf("Compared %s and %s", model.CourseURL(courseA.CourseNumber, yearA), model.CourseURL(courseB.CourseNumber, yearB)),
+		Color:       0x606060,
+		CreatedAt:   time.Now(),
+		PageSize:    5,
+	}
+	pm.Put(paginationID, data)
+
+	if err := utils.EditPaginationResponse(s, i, paginationID, data); err != nil {
+		log.Println("Failed to respond with course diff embed:", err)
+	}
+}
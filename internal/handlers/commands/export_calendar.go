@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxExportCourses bounds how many courses are fetched for a single calendar export.
+const maxExportCourses = 10
+
+// ExportCalendar handles /export_calendar. It exports the given courses, or the user's saved
+// schedule if none are given, as an .ics file that can be imported into Google or Outlook.
+func ExportCalendar(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+
+	semester, err := semesterFromOptions(options)
+	if err != nil {
+		editContent(s, i, err.Error())
+		return
+	}
+
+	var courseIDs []string
+	if opt, ok := options["courses"]; ok {
+		courseIDs = parseCourseList(opt.StringValue())
+	}
+	if len(courseIDs) == 0 {
+		store, err := model.GetScheduleStore()
+		if err != nil {
+			log.Println("Error loading schedules:", err)
+			editContent(s, i, "Your schedule could not be loaded right now.")
+			return
+		}
+		courseIDs = store.Courses(utils.InteractionUserID(i), semester)
+	}
+
+	if len(courseIDs) == 0 {
+		editContent(s, i, fmt.Sprintf("Give one or more course codes, or add them to your %s schedule with `/schedule add`.", semester))
+		return
+	}
+	if len(courseIDs) > maxExportCourses {
+		editContent(s, i, fmt.Sprintf("At most %d courses can be exported at once.", maxExportCourses))
+		return
+	}
+	for _, courseID := range courseIDs {
+		if err := model.ValidateCourseNumber(courseID); err != nil {
+			respondWithFetchError(s, i, courseID, err)
+			return
+		}
+	}
+
+	courses, failed := fetchCourses(courseIDs, semester.AcademicYear())
+	if len(courses) == 0 {
+		editContent(s, i, "None of the courses could be fetched right now. Please try again later.")
+		return
+	}
+
+	data, err := model.BuildCalendar(courses, semester, time.Now())
+	if errors.Is(err, model.ErrCalendarNotConfigured) {
+		editContent(s, i, fmt.Sprintf("The academic calendar is not configured for %s yet, so %s cannot be exported.",
+			semester.AcademicYear(), semester))
+		return
+	}
+	if err != nil {
+		log.Println("Error building calendar:", err)
+		editContent(s, i, "The calendar could not be created right now.")
+		return
+	}
+
+	content := fmt.Sprintf("Calendar for %s with %s.", semester, courseNumbers(courses))
+	if len(failed) > 0 {
+		content += fmt.Sprintf("\nCould not be fetched: %s", strings.Join(failed, ", "))
+	}
+	edit := &discordgo.WebhookEdit{
+		Content: &content,
+		Files: []*discordgo.File{{
+			Name:        fmt.Sprintf("dtu-%s-%d.ics", strings.ToLower(string(semester.Semester)), semester.Year),
+			ContentType: "text/calendar",
+			Reader:      bytes.NewReader(data),
+		}},
+	}
+	if err := utils.EditResponse(s, i, edit); err != nil {
+		log.Println("Failed to respond with calendar file:", err)
+	}
+}
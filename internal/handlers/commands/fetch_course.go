@@ -1,8 +1,10 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
@@ -10,70 +12,162 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// RetryFetchCoursePrefix prefixes the custom ID of the "Retry" button shown when a fetch fails.
+const RetryFetchCoursePrefix = "retry_fetch"
+
+// FetchCourse handles /fetch_course. It is registered as a deferred handler
+// since rendering the course page regularly takes longer than 3 seconds.
 func FetchCourse(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
-	courseID := i.ApplicationCommandData().Options[0].StringValue()
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := normalizeCourseCode(options["course_code"].StringValue())
 
-	// Fetch the course
-	course, err := model.FetchCourse(courseID)
-	if err != nil {
-		log.Printf("Error fetching course: %v", err)
-		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "Error fetching event data.",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
+	respondWithCourse(s, i, pm, courseID, yearFromOptions(options))
+}
+
+// yearFromOptions returns the academic year given in the "year" option, or the current one.
+func yearFromOptions(options map[string]*discordgo.ApplicationCommandInteractionDataOption) string {
+	if opt, ok := options["year"]; ok && strings.TrimSpace(opt.StringValue()) != "" {
+		return strings.TrimSpace(opt.StringValue())
 	}
+	return model.CurrentAcademicYear(time.Now())
+}
 
-	if course == nil {
-		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: fmt.Sprintf("No course found for ID: %s", courseID),
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
+// RetryFetchCourse handles the "Retry" button of a failed fetch by running the fetch again.
+// Like FetchCourse it must be registered as a deferred handler.
+func RetryFetchCourse(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	// The custom ID is "retry_fetch_<course>_<year>"; buttons from before the year was added lack it.
+	rest := strings.TrimPrefix(i.MessageComponentData().CustomID, RetryFetchCoursePrefix+"_")
+	courseID, year, ok := strings.Cut(rest, "_")
+	if !ok {
+		year = model.CurrentAcademicYear(time.Now())
 	}
+	respondWithCourse(s, i, pm, normalizeCourseCode(courseID), year)
+}
 
-	// Save the course as course number, title
-	err = model.SaveCourseToFile(fmt.Sprintf("%s, %s", course.CourseNumber, course.Title))
+// respondWithCourse fetches the course in the academic year and replaces the deferred response
+// with a paginated embed.
+func respondWithCourse(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions, courseID, year string) {
+	// Fetch the course
+	course, err := model.FetchCourseForYear(courseID, year)
 	if err != nil {
-		log.Println("Failed to save course to file:", err)
+		log.Printf("Error fetching course: %v", err)
+		respondWithFetchError(s, i, courseID, err)
+		return
 	}
 
+	// Record the course in the course store, which autocompletion is served from
+	model.StoreCourses(course)
+
 	// Create a new paginated session
 	paginationID := utils.BuildPaginationID()
+	data := coursePaginationData(course, utils.InteractionUserID(i))
+	pm.Put(paginationID, data)
+
+	// The interaction was deferred, so we replace the "thinking" message with the embed.
+	// The week grid is attached as a file, so it stays in place while paging through the embed.
+	if err := utils.EditPaginationResponse(s, i, paginationID, data, courseTimetableImage(course)...); err != nil {
+		log.Println("Failed to respond with course embed:", err)
+	}
+}
 
+// coursePaginationData lays out the course as a paginated embed: the information table
+// on the first page and the description sections on the following page(s).
+func coursePaginationData(course *model.Course, authorID string) *utils.PaginationData {
 	fields := make([]utils.Section, 0)
 
-	// log the course GetSectionValue() lengths
 	fields = append(fields, course)
 	fields = append(fields, course.CourseScheduleSection)
 	fields = append(fields, course.CourseExamSection)
 	fields = append(fields, course.CourseResponsibleSection)
 	fields = append(fields, course.CourseAdditionalSection)
+	// The description sections end up on the following page(s)
+	fields = append(fields, course.DescriptionSections()...)
 	// for _, courseType := range course.CourseTypeSection.CourseType {
 	// 	fields = append(fields, courseType)
 	// }
 
-	// Create and store the PaginationData
-	data := &utils.PaginationData{
+	return &utils.PaginationData{
 		Fields:      fields,
 		PageIndex:   0,
 		Description: "",
-		AuthorID:    i.Member.User.ID,
-		Title:       fmt.Sprintf("Fetched course: %s - %s", courseID, course.Title),
-		Footer:      fmt.Sprintf("Fetched from %s", fmt.Sprintf("https://kurser.dtu.dk/course/%s", course.CourseNumber)),
+		AuthorID:    authorID,
+		Title:       fmt.Sprintf("Fetched course: %s - %s (%s)", course.CourseNumber, course.Title, course.AcademicYear),
+		Footer:      fmt.Sprintf("Fetched from %s", model.CourseURL(course.CourseNumber, course.AcademicYear)),
 		Color:       0x606060,
 		CreatedAt:   time.Now(),
 		PageSize:    5,
 	}
-	pm.Put(paginationID, data)
+}
 
-	if err := utils.SendInitialPaginationResponse(s, i, paginationID, data); err != nil {
-		log.Println("Failed to respond with course embed:", err)
+// courseTimetableImage renders the weekly slots of a single course, in the first semester it is taught.
+func courseTimetableImage(course *model.Course) []*discordgo.File {
+	semester := model.CurrentSemester(time.Now())
+	for _, slot := range course.CourseScheduleSection.ParsedSchedule().Slots {
+		if slot.Semester != "" {
+			semester.Semester = slot.Semester
+			break
+		}
 	}
+
+	timetable := model.BuildTimetable([]*model.Course{course}, semester)
+	file := timetableImage(timetable, fmt.Sprintf("%s - %s (%s)", course.CourseNumber, course.Title, semester.Semester))
+	if file == nil {
+		return nil
+	}
+	return []*discordgo.File{file}
+}
+
+// respondWithFetchError tells the user why the fetch failed, with a "Retry" button
+// for errors that might go away on their own.
+func respondWithFetchError(s *discordgo.Session, i *discordgo.InteractionCreate, courseID string, err error) {
+	var message string
+	retryable := true
+
+	var year string
+	var fetchErr *model.FetchError
+	if errors.As(err, &fetchErr) {
+		year = fetchErr.AcademicYear
+	}
+
+	switch {
+	case errors.Is(err, model.ErrInvalidCourseNumber):
+		message = fmt.Sprintf("`%s` is not a valid course number. DTU course numbers have five characters, e.g. `02105`.", courseID)
+		retryable = false
+	case errors.Is(err, model.ErrInvalidAcademicYear):
+		message = fmt.Sprintf("`%s` is not a valid academic year. Academic years are written like `2025-2026`.", year)
+		retryable = false
+	case errors.Is(err, model.ErrCourseNotFound) && year != "":
+		message = fmt.Sprintf("No course found for ID: %s in %s", courseID, year)
+		retryable = false
+	case errors.Is(err, model.ErrCourseNotFound):
+		message = fmt.Sprintf("No course found for ID: %s", courseID)
+		retryable = false
+	case errors.Is(err, model.ErrFetchTimeout):
+		message = fmt.Sprintf("The course page for %s took too long to load. DTU's course site may be slow right now.", courseID)
+	case errors.Is(err, model.ErrSiteUnavailable):
+		message = "DTU's course site could not be reached. Please try again in a moment."
+	case errors.Is(err, model.ErrParseFailure):
+		message = fmt.Sprintf("The course page for %s was fetched, but could not be read.", courseID)
+	default:
+		message = fmt.Sprintf("Something went wrong while fetching course %s.", courseID)
+	}
+
+	if !retryable {
+		utils.ReplaceWithEphemeral(s, i, message)
+		return
+	}
+
+	customID := RetryFetchCoursePrefix + "_" + courseID
+	if year != "" {
+		customID += "_" + year
+	}
+	utils.ReplaceWithEphemeral(s, i, message, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Retry",
+				Style:    discordgo.SecondaryButton,
+				CustomID: customID,
+			},
+		},
+	})
 }
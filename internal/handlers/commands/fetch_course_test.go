@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+)
+
+// serveCatalogue starts a local course catalogue serving the recorded pages of the model tests,
+// and points the configuration, course source and cache at it.
+func serveCatalogue(t *testing.T) *httptest.Server {
+	t.Helper()
+	page, err := os.ReadFile("../../model/testdata/course_02105_en.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notFound, err := os.ReadFile("../../model/testdata/course_not_found.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/02105"):
+			w.Write(page)
+		case strings.HasSuffix(r.URL.Path, "/99999"):
+			// The catalogue answers unknown courses with an empty page rather than a 404.
+			w.Write(notFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// Fetched courses are indexed under data/, so run in a scratch directory.
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	t.Setenv("COURSE_CATALOGUE_URL", srv.URL+"/")
+	t.Setenv("COURSE_CACHE_DIR", filepath.Join(dir, "cache"))
+	config.LoadConfig()
+	model.SetCourseSource(model.HTTPSource{})
+	model.SetCourseCache(model.NewCourseCache(config.GlobalConfig.CourseCacheDir, time.Hour))
+	return srv
+}
+
+func TestFetchCourseFromLocalCatalogue(t *testing.T) {
+	srv := serveCatalogue(t)
+	year := model.CurrentAcademicYear(time.Now())
+
+	course, err := model.FetchCourse("02105")
+	if err != nil {
+		t.Fatalf("FetchCourse: %v", err)
+	}
+	if course.Title != "Algorithms and Data Structures 1" || course.AcademicYear != year {
+		t.Errorf("fetched %q (%s), want Algorithms and Data Structures 1 (%s)", course.Title, course.AcademicYear, year)
+	}
+
+	embed := utils.MakePaginationEmbed(coursePaginationData(course, "user"))
+	if want := "Fetched course: 02105 - Algorithms and Data Structures 1 (" + year + ")"; embed.Title != want {
+		t.Errorf("embed title = %q, want %q", embed.Title, want)
+	}
+	wantURL := srv.URL + "/course/" + year + "/02105"
+	if !strings.Contains(embed.Footer.Text, "Fetched from "+wantURL) {
+		t.Errorf("embed footer = %q, want it to link %s", embed.Footer.Text, wantURL)
+	}
+	if len(embed.Fields) == 0 || embed.Fields[0].Name != "**Course: 02105 - Algorithms and Data Structures 1**" {
+		t.Errorf("first embed field = %+v, want the course overview", embed.Fields)
+	}
+
+	// The second fetch is served from the cache.
+	cached, err := model.FetchCourse("02105")
+	if err != nil {
+		t.Fatalf("FetchCourse from cache: %v", err)
+	}
+	if !cached.CourseAdditionalSection.Cached {
+		t.Error("second fetch was not served from the cache")
+	}
+	// Course numbers are normalized before they key the cache.
+	spaced, err := model.FetchCourse(" 02105 ")
+	if err != nil {
+		t.Fatalf("FetchCourse with spaces: %v", err)
+	}
+	if spaced.CourseNumber != "02105" || !spaced.CourseAdditionalSection.Cached {
+		t.Errorf("FetchCourse(\" 02105 \") = %s (cached %t), want the cached 02105", spaced.CourseNumber, spaced.CourseAdditionalSection.Cached)
+	}
+}
+
+func TestFetchCourseNotInLocalCatalogue(t *testing.T) {
+	serveCatalogue(t)
+
+	for _, courseNumber := range []string{"99999", "88888"} {
+		if _, err := model.FetchCourse(courseNumber); !errors.Is(err, model.ErrCourseNotFound) {
+			t.Errorf("FetchCourse(%s): error = %v, want ErrCourseNotFound", courseNumber, err)
+		}
+	}
+}
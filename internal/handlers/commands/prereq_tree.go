@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxPrereqFetches bounds how many unknown prerequisites are fetched for a single tree.
+	maxPrereqFetches = 15
+	// maxEmbedDescriptionLength is the longest description Discord accepts for an embed.
+	maxEmbedDescriptionLength = 4096
+)
+
+// PrereqTree handles /prereq_tree. It shows the recursive prerequisites of a course in the
+// academic year as an indented tree, built from the reference index and fetching the courses
+// still missing. The index only holds current versions, so for other years every course is fetched.
+func PrereqTree(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := strings.ToUpper(strings.TrimSpace(options["course_code"].StringValue()))
+
+	year := yearFromOptions(options)
+	course, err := model.FetchCourseForYear(courseID, year)
+	if err != nil {
+		log.Printf("Error fetching course: %v", err)
+		respondWithFetchError(s, i, courseID, err)
+		return
+	}
+
+	// Start from the prerequisites of every course fetched so far, which the reference index keeps in memory.
+	graph := model.NewPrerequisiteGraph()
+	if year == model.CurrentAcademicYear(time.Now()) {
+		if index, err := model.GetReferenceIndex(); err != nil {
+			log.Println("Error loading the reference index:", err)
+		} else {
+			graph = index.PrerequisiteGraph()
+		}
+	}
+	graph.Add(course)
+
+	// Fetch the prerequisites that have not been seen before, level by level, within a budget.
+	failed := make(map[string]bool)
+	fetches := 0
+	for fetches < maxPrereqFetches {
+		var pending []string
+		for _, courseNumber := range graph.Missing(course.CourseNumber) {
+			if !failed[courseNumber] {
+				pending = append(pending, courseNumber)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		for _, courseNumber := range pending {
+			if fetches == maxPrereqFetches {
+				break
+			}
+			fetches++
+			prerequisite, err := model.FetchCourseForYear(courseNumber, year)
+			if err != nil {
+				log.Printf("Error fetching prerequisite: %v", err)
+				failed[courseNumber] = true
+				continue
+			}
+			graph.Add(prerequisite)
+		}
+	}
+
+	tree := graph.Tree(course.CourseNumber)
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Prerequisites of %s - %s", course.CourseNumber, course.Title),
+		URL:         model.CourseURL(course.CourseNumber, course.AcademicYear),
+		Description: "```\n" + utils.Truncate(tree, maxEmbedDescriptionLength-8) + "\n```",
+		Color:       0x606060,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if len(graph.Prerequisites[course.CourseNumber]) == 0 {
+		embed.Description = "This course does not name any other courses as prerequisites."
+	}
+	if remainder := course.CourseAdditionalSection.Prerequisites().Remainder; remainder != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Other prerequisites",
+			Value: utils.Truncate(remainder, utils.MaxFieldValueLength),
+		})
+	}
+	if missing := graph.Missing(course.CourseNumber); len(missing) > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("The prerequisites of %d course(s) could not be fetched and are not expanded.", len(missing)),
+		}
+	}
+
+	embeds := []*discordgo.MessageEmbed{embed}
+	edit := &discordgo.WebhookEdit{Embeds: &embeds}
+	if opt, ok := options["dot"]; ok && opt.BoolValue() {
+		edit.Files = []*discordgo.File{{
+			Name:        fmt.Sprintf("prerequisites-%s.dot", course.CourseNumber),
+			ContentType: "text/vnd.graphviz",
+			Reader:      strings.NewReader(graph.DOT(course.CourseNumber)),
+		}}
+	}
+	if err := utils.EditResponse(s, i, edit); err != nil {
+		log.Println("Failed to respond with prerequisite tree:", err)
+	}
+}
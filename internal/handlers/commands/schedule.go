@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Schedule handles /schedule add|remove|show. It is registered as a deferred ephemeral
+// handler, since a personal schedule is only interesting to its owner.
+func Schedule(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := utils.OptionMap(sub.Options)
+
+	semester, err := semesterFromOptions(options)
+	if err != nil {
+		editContent(s, i, err.Error())
+		return
+	}
+
+	store, err := model.GetScheduleStore()
+	if err != nil {
+		log.Println("Error loading schedules:", err)
+		editContent(s, i, "Your schedule could not be loaded right now.")
+		return
+	}
+
+	userID := utils.InteractionUserID(i)
+	switch sub.Name {
+	case "add":
+		scheduleAdd(s, i, store, userID, semester, normalizeCourseCode(options["course_code"].StringValue()))
+	case "remove":
+		scheduleRemove(s, i, store, userID, semester, normalizeCourseCode(options["course_code"].StringValue()))
+	case "show":
+		scheduleShow(s, i, store, userID, semester)
+	}
+}
+
+// normalizeCourseCode trims and upper-cases a course code typed by the user, e.g. " 4210x" -> "4210X",
+// which is how course numbers are stored.
+func normalizeCourseCode(courseID string) string {
+	return strings.ToUpper(strings.TrimSpace(courseID))
+}
+
+// semesterFromOptions returns the semester given in the "semester" option, or the current one.
+func semesterFromOptions(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (model.SemesterKey, error) {
+	if opt, ok := options["semester"]; ok && strings.TrimSpace(opt.StringValue()) != "" {
+		return model.ParseSemesterKey(opt.StringValue())
+	}
+	return model.CurrentSemester(time.Now()), nil
+}
+
+func scheduleAdd(s *discordgo.Session, i *discordgo.InteractionCreate, store *model.ScheduleStore, userID string, semester model.SemesterKey, courseID string) {
+	// Fetch the course first, so only existing courses end up in the schedule.
+	course, err := model.FetchCourseForYear(courseID, semester.AcademicYear())
+	if err != nil {
+		log.Printf("Error fetching course: %v", err)
+		respondWithFetchError(s, i, courseID, err)
+		return
+	}
+
+	added, err := store.Add(userID, semester, course.CourseNumber)
+	if err != nil {
+		log.Println("Error saving schedule:", err)
+		editContent(s, i, "Your schedule could not be saved right now.")
+		return
+	}
+	if !added {
+		editContent(s, i, fmt.Sprintf("%s - %s is already in your %s schedule.", course.CourseNumber, course.Title, semester))
+		return
+	}
+	editContent(s, i, fmt.Sprintf("Added %s - %s to your %s schedule.", course.CourseNumber, course.Title, semester))
+}
+
+func scheduleRemove(s *discordgo.Session, i *discordgo.InteractionCreate, store *model.ScheduleStore, userID string, semester model.SemesterKey, courseID string) {
+	removed, err := store.Remove(userID, semester, courseID)
+	if err != nil {
+		log.Println("Error saving schedule:", err)
+		editContent(s, i, "Your schedule could not be saved right now.")
+		return
+	}
+	if !removed {
+		editContent(s, i, fmt.Sprintf("%s is not in your %s schedule.", courseID, semester))
+		return
+	}
+	editContent(s, i, fmt.Sprintf("Removed %s from your %s schedule.", courseID, semester))
+}
+
+func scheduleShow(s *discordgo.Session, i *discordgo.InteractionCreate, store *model.ScheduleStore, userID string, semester model.SemesterKey) {
+	courseIDs := store.Courses(userID, semester)
+	if len(courseIDs) == 0 {
+		editContent(s, i, fmt.Sprintf("Your %s schedule is empty. Add courses with `/schedule add`.", semester))
+		return
+	}
+
+	courses, failed := fetchCourses(courseIDs, semester.AcademicYear())
+	timetable := model.BuildTimetable(courses, semester)
+
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("Your schedule: %s", semester),
+		Color:     0x606060,
+		Fields:    timetableFields(timetable, failed),
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	edit := &discordgo.WebhookEdit{}
+
+	// Show the week grid as an image inside the embed, if there is anything to draw.
+	if file := timetableImage(timetable, embed.Title); file != nil {
+		embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + file.Name}
+		edit.Files = []*discordgo.File{file}
+	}
+
+	embeds := []*discordgo.MessageEmbed{embed}
+	edit.Embeds = &embeds
+	if err := utils.EditResponse(s, i, edit); err != nil {
+		log.Println("Failed to respond with schedule embed:", err)
+	}
+}
+
+// timetableImage renders the timetable as a PNG attachment,
+// or returns nil if it has no weekly slots to draw.
+func timetableImage(timetable model.Timetable, title string) *discordgo.File {
+	if len(timetable.Days) == 0 {
+		return nil
+	}
+
+	data, err := model.RenderTimetablePNG(timetable, title)
+	if err != nil {
+		log.Println("Failed to render timetable image:", err)
+		return nil
+	}
+	return &discordgo.File{
+		Name:        "timetable.png",
+		ContentType: "image/png",
+		Reader:      bytes.NewReader(data),
+	}
+}
+
+// fetchCourses fetches the version of each of the courses for the academic year,
+// returning the ones that failed separately.
+func fetchCourses(courseIDs []string, year string) (courses []*model.Course, failed []string) {
+	for _, courseID := range courseIDs {
+		course, err := model.FetchCourseForYear(courseID, year)
+		if err != nil {
+			log.Printf("Error fetching course %s: %v", courseID, err)
+			failed = append(failed, courseID)
+			continue
+		}
+		courses = append(courses, course)
+	}
+	return courses, failed
+}
+
+// timetableFields renders the timetable as one embed field per weekday,
+// followed by the 3-week periods and anything that could not be placed.
+func timetableFields(timetable model.Timetable, failed []string) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+
+	for day := time.Monday; day <= time.Friday; day++ {
+		entries := timetable.Days[day]
+		if len(entries) == 0 {
+			continue
+		}
+		var sb strings.Builder
+		for _, entry := range entries {
+			sb.WriteString(fmt.Sprintf("`%s-%s` **%s** %s (%s)\n",
+				model.FormatClock(entry.Slot.Start), model.FormatClock(entry.Slot.End),
+				entry.Course.CourseNumber, entry.Course.Title, entry.Slot.Block))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  day.String(),
+			Value: utils.Truncate(sb.String(), utils.MaxFieldValueLength),
+		})
+	}
+
+	for _, period := range model.ThreeWeekPeriods {
+		courses := timetable.ThreeWeek[period]
+		if len(courses) == 0 {
+			continue
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s (3-week period)", period),
+			Value: utils.Truncate(courseList(courses), utils.MaxFieldValueLength),
+		})
+	}
+
+	if len(timetable.Unscheduled) > 0 {
+		var sb strings.Builder
+		for _, course := range timetable.Unscheduled {
+			sb.WriteString(fmt.Sprintf("**%s** %s: %s\n", course.CourseNumber, course.Title, orDash(course.CourseScheduleSection.Schedule)))
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Unknown schedule",
+			Value: utils.Truncate(sb.String(), utils.MaxFieldValueLength),
+		})
+	}
+
+	if len(failed) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Could not be fetched",
+			Value: utils.Truncate(strings.Join(failed, ", "), utils.MaxFieldValueLength),
+		})
+	}
+
+	if len(fields) == 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Nothing this semester",
+			Value: fmt.Sprintf("None of your courses are taught in %s.", timetable.Semester),
+		})
+	}
+	return fields
+}
+
+// courseList formats courses as one "**number** title" line each.
+func courseList(courses []*model.Course) string {
+	var sb strings.Builder
+	for _, course := range courses {
+		sb.WriteString(fmt.Sprintf("**%s** %s\n", course.CourseNumber, course.Title))
+	}
+	return sb.String()
+}
+
+// orDash returns "-" for empty values, since Discord rejects empty embed text.
+func orDash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// editContent replaces the deferred response with a plain text message.
+func editContent(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	if err := utils.EditResponseContent(s, i, content); err != nil {
+		log.Println("Failed to edit response:", err)
+	}
+}
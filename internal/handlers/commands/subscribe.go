@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Subscribe handles /subscribe. The user is notified by direct message, or in the current
+// channel, whenever the course page changes. It is registered as a deferred ephemeral handler.
+func Subscribe(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := strings.ToUpper(strings.TrimSpace(options["course_code"].StringValue()))
+
+	sub := model.Subscription{UserID: utils.InteractionUserID(i)}
+	if opt, ok := options["notify_in"]; ok && opt.StringValue() == "channel" {
+		sub.ChannelID = i.ChannelID
+	}
+
+	// Fetch the course first, so only existing courses can be subscribed to. Subscriptions follow
+	// the current version of the course, which is what the subscription checks compare against.
+	course, err := model.FetchCourseForYear(courseID, model.CurrentAcademicYear(time.Now()))
+	if err != nil {
+		log.Printf("Error fetching course: %v", err)
+		respondWithFetchError(s, i, courseID, err)
+		return
+	}
+
+	store, err := model.GetSubscriptionStore()
+	if err != nil {
+		log.Println("Error loading subscriptions:", err)
+		editContent(s, i, "Your subscription could not be saved right now.")
+		return
+	}
+
+	// The first subscriber's version of the course is what later versions are compared against.
+	if snapshot, err := store.Snapshot(course.CourseNumber); err == nil && snapshot == nil {
+		if err := store.SaveSnapshot(course); err != nil {
+			log.Println("Error saving course snapshot:", err)
+		}
+	}
+
+	added, err := store.Subscribe(course.CourseNumber, sub)
+	if err != nil {
+		log.Println("Error saving subscriptions:", err)
+		editContent(s, i, "Your subscription could not be saved right now.")
+		return
+	}
+
+	where := "by direct message"
+	if sub.ChannelID != "" {
+		where = "in this channel"
+	}
+	if !added {
+		editContent(s, i, fmt.Sprintf("You are subscribed to %s - %s. Changes will be posted %s.", course.CourseNumber, course.Title, where))
+		return
+	}
+	editContent(s, i, fmt.Sprintf("Subscribed to %s - %s. You will be notified %s when the course page changes.", course.CourseNumber, course.Title, where))
+}
+
+// Unsubscribe handles /unsubscribe. It is registered as a deferred ephemeral handler.
+func Unsubscribe(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := strings.ToUpper(strings.TrimSpace(options["course_code"].StringValue()))
+	userID := utils.InteractionUserID(i)
+
+	store, err := model.GetSubscriptionStore()
+	if err != nil {
+		log.Println("Error loading subscriptions:", err)
+		editContent(s, i, "Your subscriptions could not be loaded right now.")
+		return
+	}
+
+	removed, err := store.Unsubscribe(courseID, userID)
+	if err != nil {
+		log.Println("Error saving subscriptions:", err)
+		editContent(s, i, "Your subscription could not be removed right now.")
+		return
+	}
+	if removed {
+		editContent(s, i, fmt.Sprintf("Unsubscribed from %s.", courseID))
+		return
+	}
+
+	courses := store.UserCourses(userID)
+	if len(courses) == 0 {
+		editContent(s, i, fmt.Sprintf("You are not subscribed to %s, or any other course.", courseID))
+		return
+	}
+	editContent(s, i, fmt.Sprintf("You are not subscribed to %s. Your subscriptions: %s", courseID, strings.Join(courses, ", ")))
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Unlocks handles /unlocks. It lists every known course that names the given course
+// as an academic prerequisite.
+func Unlocks(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	respondWithReferences(s, i, func(index *model.ReferenceIndex, course *model.Course) (string, string, []string) {
+		return fmt.Sprintf("Courses that build on %s - %s", course.CourseNumber, course.Title),
+			"No known course lists this course as a prerequisite.",
+			index.Unlocks(course.CourseNumber)
+	})
+}
+
+// MutuallyExclusive handles /mutually_exclusive. It lists the courses that cannot be
+// taken together with the given course, whichever of the two courses states it.
+func MutuallyExclusive(s *discordgo.Session, i *discordgo.InteractionCreate, pm *utils.PaginatedSessions) {
+	respondWithReferences(s, i, func(index *model.ReferenceIndex, course *model.Course) (string, string, []string) {
+		return fmt.Sprintf("Courses not applicable together with %s - %s", course.CourseNumber, course.Title),
+			"No known course is mutually exclusive with this course.",
+			index.MutuallyExclusive(course.CourseNumber)
+	})
+}
+
+// respondWithReferences fetches the course of the "course_code" option, so that it is indexed,
+// and lists the courses returned by lookup in an embed. The reference index only holds the
+// current version of each course, so the course is looked up in the current academic year too.
+func respondWithReferences(s *discordgo.Session, i *discordgo.InteractionCreate,
+	lookup func(index *model.ReferenceIndex, course *model.Course) (title, empty string, courseNumbers []string)) {
+	options := utils.OptionMap(i.ApplicationCommandData().Options)
+	courseID := strings.ToUpper(strings.TrimSpace(options["course_code"].StringValue()))
+
+	course, err := model.FetchCourseForYear(courseID, model.CurrentAcademicYear(time.Now()))
+	if err != nil {
+		log.Printf("Error fetching course: %v", err)
+		respondWithFetchError(s, i, courseID, err)
+		return
+	}
+
+	index, err := model.GetReferenceIndex()
+	if err != nil {
+		log.Println("Error loading the reference index:", err)
+		editContent(s, i, "The course index could not be loaded right now.")
+		return
+	}
+	// A course served from the cache may not have been indexed yet.
+	if err := index.Update(course); err != nil {
+		log.Println("Error saving the reference index:", err)
+	}
+
+	title, empty, courseNumbers := lookup(index, course)
+	description := empty
+	if len(courseNumbers) > 0 {
+		var sb strings.Builder
+		for _, courseNumber := range courseNumbers {
+			sb.WriteString(strings.TrimSpace(fmt.Sprintf("**%s** %s", courseNumber, referenceTitle(index, courseNumber))) + "\n")
+		}
+		description = utils.Truncate(sb.String(), maxEmbedDescriptionLength)
+	}
+
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       title,
+		URL:         model.CourseURL(course.CourseNumber, course.AcademicYear),
+		Description: description,
+		Color:       0x606060,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Based on the %d courses fetched so far", index.Len()),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}}
+	if err := utils.EditResponse(s, i, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+		log.Println("Failed to respond with course references:", err)
+	}
+}
+
+// referenceTitle returns the title of a course found in the reference index,
+// looking it up in the course store if the index does not know it.
+func referenceTitle(index *model.ReferenceIndex, courseNumber string) string {
+	if courseTitle, ok := index.Title(courseNumber); ok {
+		return courseTitle
+	}
+	store, err := model.GetCourseStore()
+	if err != nil {
+		log.Println("Error loading the course store:", err)
+		return ""
+	}
+	entry, _ := store.Get(courseNumber)
+	return entry.Title
+}
@@ -0,0 +1,322 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+)
+
+// ErrCalendarNotConfigured is returned when the academic calendar has no dates for a semester.
+var ErrCalendarNotConfigured = errors.New("academic calendar not configured")
+
+// dateLayout is the format of dates in the academic calendar.
+const dateLayout = "2006-01-02"
+
+// DateRange is an inclusive range of days, at midnight UTC.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Contains reports whether the day of t falls within the range.
+func (r DateRange) Contains(t time.Time) bool {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(r.Start) && !day.After(r.End)
+}
+
+func (r DateRange) String() string {
+	return fmt.Sprintf("%s - %s", r.Start.Format("2 Jan 2006"), r.End.Format("2 Jan 2006"))
+}
+
+// UnmarshalJSON reads a range written as {"start": "2025-09-01", "end": "2025-12-05"}.
+func (r *DateRange) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	start, err := time.Parse(dateLayout, raw.Start)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(dateLayout, raw.End)
+	if err != nil {
+		return err
+	}
+	if end.Before(start) {
+		return fmt.Errorf("date range %s..%s ends before it starts", raw.Start, raw.End)
+	}
+	r.Start, r.End = start, end
+	return nil
+}
+
+// defaultTeachingWeeks is the number of teaching weeks of a semester whose calendar does not say.
+const defaultTeachingWeeks = 13
+
+// SemesterPeriod is the teaching period of a semester.
+type SemesterPeriod struct {
+	DateRange
+	// TeachingWeeks is the number of weeks with teaching, which excludes holiday weeks such as the autumn break.
+	TeachingWeeks int `json:"teachingWeeks"`
+}
+
+func (p *SemesterPeriod) UnmarshalJSON(data []byte) error {
+	var weeks struct {
+		TeachingWeeks int `json:"teachingWeeks"`
+	}
+	if err := json.Unmarshal(data, &weeks); err != nil {
+		return err
+	}
+	if weeks.TeachingWeeks < 0 {
+		return fmt.Errorf("negative number of teaching weeks %d", weeks.TeachingWeeks)
+	}
+	p.TeachingWeeks = weeks.TeachingWeeks
+	return json.Unmarshal(data, &p.DateRange)
+}
+
+// Weeks returns the number of teaching weeks, which defaults to defaultTeachingWeeks.
+func (p SemesterPeriod) Weeks() int {
+	if p.TeachingWeeks == 0 {
+		return defaultTeachingWeeks
+	}
+	return p.TeachingWeeks
+}
+
+// Holiday is a period without teaching, such as the autumn break or Easter.
+type Holiday struct {
+	Name string `json:"name"`
+	DateRange
+}
+
+func (h *Holiday) UnmarshalJSON(data []byte) error {
+	var name struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	h.Name = name.Name
+	return json.Unmarshal(data, &h.DateRange)
+}
+
+// AcademicYearCalendar holds the dates of one academic year, e.g. "2025-2026".
+type AcademicYearCalendar struct {
+	Autumn SemesterPeriod `json:"autumn"`
+	Spring SemesterPeriod `json:"spring"`
+	// ThreeWeekPeriods are keyed by their canonical name, e.g. "January".
+	ThreeWeekPeriods map[string]DateRange `json:"threeWeekPeriods"`
+	// ExamPeriods are keyed by their English name, e.g. "Winter exam".
+	ExamPeriods map[string]DateRange `json:"examPeriods"`
+	Holidays    []Holiday            `json:"holidays"`
+	// ExamDays are the dates of the exams placed on a block code, e.g. "E3A": "2025-12-12".
+	// Codes without a date are only known to fall in the exam period of their semester.
+	ExamDays map[string]string `json:"examDays"`
+}
+
+// AcademicCalendar holds the dates of each academic year, keyed like "2025-2026".
+type AcademicCalendar struct {
+	Years map[string]AcademicYearCalendar `json:"years"`
+}
+
+// LoadAcademicCalendar reads the calendar from path. A missing file gives an empty calendar.
+func LoadAcademicCalendar(path string) (*AcademicCalendar, error) {
+	calendar := &AcademicCalendar{Years: make(map[string]AcademicYearCalendar)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return calendar, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, calendar); err != nil {
+		return nil, fmt.Errorf("invalid academic calendar %s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+// calendarAliases maps Danish and alternative names to the names used in the calendar file.
+var calendarAliases = map[string]string{
+	"efterår":       "autumn",
+	"fall":          "autumn",
+	"forår":         "spring",
+	"januar":        "january",
+	"juni":          "june",
+	"juli":          "july",
+	"vintereksamen": "winter exam",
+	"december exam": "winter exam",
+	"sommereksamen": "summer exam",
+	"maj eksamen":   "summer exam",
+	"may exam":      "summer exam",
+	"reeksamen":     "re-exam",
+	"re-eksamen":    "re-exam",
+}
+
+// Year returns the calendar of the academic year, e.g. "2025-2026".
+func (c *AcademicCalendar) Year(academicYear string) (AcademicYearCalendar, bool) {
+	year, ok := c.Years[academicYear]
+	return year, ok
+}
+
+// Semester returns the teaching period of the semester.
+func (c *AcademicCalendar) Semester(semester SemesterKey) (SemesterPeriod, bool) {
+	year, ok := c.Year(semester.AcademicYear())
+	if !ok {
+		return SemesterPeriod{}, false
+	}
+	period := year.Autumn
+	if semester.Semester == SemesterSpring {
+		period = year.Spring
+	}
+	return period, !period.Start.IsZero()
+}
+
+// Resolve turns a period name of the academic year into a date range. It understands the
+// semesters ("Spring", "Efterår"), the 3-week periods ("January", "Juni") and the exam periods
+// ("Winter exam", "Sommereksamen"), in English or Danish and in any case.
+func (c *AcademicCalendar) Resolve(name, academicYear string) (DateRange, bool) {
+	year, ok := c.Year(academicYear)
+	if !ok {
+		return DateRange{}, false
+	}
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := calendarAliases[key]; ok {
+		key = alias
+	}
+
+	switch key {
+	case "autumn":
+		return year.Autumn.DateRange, !year.Autumn.Start.IsZero()
+	case "spring":
+		return year.Spring.DateRange, !year.Spring.Start.IsZero()
+	}
+	for _, periods := range []map[string]DateRange{year.ThreeWeekPeriods, year.ExamPeriods} {
+		for periodName, dates := range periods {
+			if strings.EqualFold(periodName, key) {
+				return dates, true
+			}
+		}
+	}
+	return DateRange{}, false
+}
+
+// ThreeWeekPeriod returns the dates of a 3-week period, e.g. "January", that belongs to the semester.
+func (c *AcademicCalendar) ThreeWeekPeriod(period string, semester SemesterKey) (DateRange, bool) {
+	return c.Resolve(period, semester.AcademicYear())
+}
+
+// TeachingWeek returns Monday to Friday of the given teaching week of the semester, counted from 1.
+// Weeks whose weekdays are all holidays, such as the autumn break, have no teaching and are not counted.
+func (c *AcademicCalendar) TeachingWeek(semester SemesterKey, week int) (DateRange, bool) {
+	period, ok := c.Semester(semester)
+	if !ok || week < 1 || week > period.Weeks() {
+		return DateRange{}, false
+	}
+
+	holidays := c.Holidays(semester.AcademicYear(), period.DateRange)
+	monday := period.Start.AddDate(0, 0, -((int(period.Start.Weekday()) + 6) % 7))
+	for ; !monday.After(period.End); monday = monday.AddDate(0, 0, 7) {
+		if holidayWeek(holidays, monday) {
+			continue
+		}
+		if week--; week == 0 {
+			return DateRange{Start: monday, End: monday.AddDate(0, 0, 4)}, true
+		}
+	}
+	return DateRange{}, false
+}
+
+// holidayWeek reports whether every weekday of the week starting on monday is a holiday.
+func holidayWeek(holidays []Holiday, monday time.Time) bool {
+	for day := 0; day < 5; day++ {
+		date := monday.AddDate(0, 0, day)
+		if !slices.ContainsFunc(holidays, func(h Holiday) bool { return h.Contains(date) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Holidays returns the holidays of the academic year that fall within the range.
+func (c *AcademicCalendar) Holidays(academicYear string, within DateRange) []Holiday {
+	year, _ := c.Year(academicYear)
+	var holidays []Holiday
+	for _, holiday := range year.Holidays {
+		if !holiday.End.Before(within.Start) && !holiday.Start.After(within.End) {
+			holidays = append(holidays, holiday)
+		}
+	}
+	return holidays
+}
+
+// ExamDay returns the date of the exam placed on a block code such as "E3A" in the academic year.
+func (c *AcademicCalendar) ExamDay(code, academicYear string) (time.Time, bool) {
+	year, _ := c.Year(academicYear)
+	value, ok := year.ExamDays[code]
+	if !ok {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		log.Printf("Ignoring invalid exam day %q for %s in the academic calendar: %v", value, code, err)
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// Exam returns the days of the exam placed on a block code such as "F3A" in the academic year:
+// the exam day itself if the calendar has it, and otherwise the exam period of the code's semester.
+func (c *AcademicCalendar) Exam(code, academicYear string) (DateRange, bool) {
+	if date, ok := c.ExamDay(code, academicYear); ok {
+		return DateRange{Start: date, End: date}, true
+	}
+	period := "Winter exam"
+	if codeSemester(code) == SemesterSpring {
+		period = "Summer exam"
+	}
+	return c.Resolve(period, academicYear)
+}
+
+var (
+	academicCalendarMu sync.Mutex
+	academicCalendar   *AcademicCalendar
+)
+
+// SetAcademicCalendar overrides the calendar loaded from config.GlobalConfig.
+func SetAcademicCalendar(calendar *AcademicCalendar) {
+	academicCalendarMu.Lock()
+	defer academicCalendarMu.Unlock()
+	academicCalendar = calendar
+}
+
+// GetAcademicCalendar returns the academic calendar, loading it from the configured file on first use.
+// If the file cannot be read, an empty calendar is used so that dates simply resolve to nothing.
+func GetAcademicCalendar() *AcademicCalendar {
+	academicCalendarMu.Lock()
+	defer academicCalendarMu.Unlock()
+
+	if academicCalendar == nil {
+		path := "data/academic_calendar.json"
+		if config.GlobalConfig != nil {
+			path = config.GlobalConfig.AcademicCalendarFile
+		}
+		calendar, err := LoadAcademicCalendar(path)
+		if err != nil {
+			log.Println("Error loading academic calendar:", err)
+			calendar = &AcademicCalendar{Years: make(map[string]AcademicYearCalendar)}
+		}
+		academicCalendar = calendar
+	}
+	return academicCalendar
+}
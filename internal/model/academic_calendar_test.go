@@ -0,0 +1,145 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShippedAcademicCalendar(t *testing.T) {
+	calendar, err := LoadAcademicCalendar("../../data/academic_calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, academicYear := range []string{"2025-2026", "2026-2027"} {
+		year, ok := calendar.Year(academicYear)
+		if !ok {
+			t.Errorf("the shipped calendar has no %s", academicYear)
+			continue
+		}
+		if !year.Autumn.Start.Before(year.Spring.Start) {
+			t.Errorf("%s: autumn %s does not come before spring %s", academicYear, year.Autumn, year.Spring)
+		}
+		for _, name := range []string{"Autumn", "Spring", "January", "June", "July", "August", "Winter exam", "Summer exam"} {
+			if _, ok := calendar.Resolve(name, academicYear); !ok {
+				t.Errorf("%s: %s has no dates", academicYear, name)
+			}
+		}
+	}
+
+	autumn, ok := calendar.Semester(SemesterKey{Semester: SemesterAutumn, Year: 2026})
+	if !ok || autumn.Start.Weekday() != time.Monday || autumn.End.Weekday() != time.Friday {
+		t.Errorf("Autumn 2026 = %s, %v, want a teaching period from a Monday to a Friday", autumn.DateRange, ok)
+	}
+}
+
+func TestShippedTeachingWeeks(t *testing.T) {
+	calendar, err := LoadAcademicCalendar("../../data/academic_calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, semester := range []SemesterKey{
+		{Semester: SemesterAutumn, Year: 2025},
+		{Semester: SemesterSpring, Year: 2026},
+		{Semester: SemesterAutumn, Year: 2026},
+		{Semester: SemesterSpring, Year: 2027},
+	} {
+		period, ok := calendar.Semester(semester)
+		if !ok || period.TeachingWeeks != 13 {
+			t.Errorf("%s has %d teaching weeks, want 13", semester, period.TeachingWeeks)
+			continue
+		}
+		first, ok := calendar.TeachingWeek(semester, 1)
+		if !ok || !first.Start.Equal(period.Start) {
+			t.Errorf("%s: week 1 = %s, want it to start on %s", semester, first, period.Start.Format(dateLayout))
+		}
+		// The semesters span 14 weeks, one of which is the autumn break or Easter.
+		last, ok := calendar.TeachingWeek(semester, period.TeachingWeeks)
+		if !ok || !last.End.Equal(period.End) {
+			t.Errorf("%s: week %d = %s, want it to end on %s", semester, period.TeachingWeeks, last, period.End.Format(dateLayout))
+		}
+		if week, ok := calendar.TeachingWeek(semester, period.TeachingWeeks+1); ok {
+			t.Errorf("%s: week %d = %s, want none", semester, period.TeachingWeeks+1, week)
+		}
+	}
+
+	// The autumn break of 2025 is the week of 13 October, so week 7 is the week after.
+	week, _ := calendar.TeachingWeek(SemesterKey{Semester: SemesterAutumn, Year: 2025}, 7)
+	if want := time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC); !week.Start.Equal(want) {
+		t.Errorf("Autumn 2025 week 7 starts on %s, want 2025-10-20", week.Start.Format(dateLayout))
+	}
+}
+
+func TestShippedExamResolution(t *testing.T) {
+	calendar, err := LoadAcademicCalendar("../../data/academic_calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	winter, _ := calendar.Resolve("Winter exam", "2025-2026")
+	summer, _ := calendar.Resolve("Summer exam", "2025-2026")
+	day := time.Date(2025, time.December, 12, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		calendar *AcademicCalendar
+		code     string
+		want     DateRange
+	}{
+		{"autumn code without an exam day", calendar, "E3A", winter},
+		{"spring code without an exam day", calendar, "F3A", summer},
+		{"code with an exam day", withExamDay(calendar, "2025-2026", "E3A", "2025-12-12"), "E3A", DateRange{Start: day, End: day}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.calendar.Exam(tc.code, "2025-2026")
+			if !ok || got != tc.want {
+				t.Errorf("Exam(%q) = %s, %v, want %s", tc.code, got, ok, tc.want)
+			}
+		})
+	}
+
+	if got, ok := calendar.Exam("F3A", "2030-2031"); ok {
+		t.Errorf("Exam in an unconfigured year = %s, want none", got)
+	}
+}
+
+// withExamDay returns a copy of the calendar with an exam day added to the academic year.
+func withExamDay(calendar *AcademicCalendar, academicYear, code, date string) *AcademicCalendar {
+	year := calendar.Years[academicYear]
+	year.ExamDays = map[string]string{code: date}
+	return &AcademicCalendar{Years: map[string]AcademicYearCalendar{academicYear: year}}
+}
+
+func TestResolveAliases(t *testing.T) {
+	calendar, err := LoadAcademicCalendar("../../data/academic_calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pair := range [][2]string{
+		{"Efterår", "autumn"},
+		{"forår", "Spring"},
+		{"Januar", "January"},
+		{"Vintereksamen", "Winter exam"},
+	} {
+		alias, _ := calendar.Resolve(pair[0], "2026-2027")
+		name, _ := calendar.Resolve(pair[1], "2026-2027")
+		if alias != name || alias.Start.IsZero() {
+			t.Errorf("Resolve(%q) = %s, want the dates of %q (%s)", pair[0], alias, pair[1], name)
+		}
+	}
+}
+
+func TestBuildCalendarWithoutConfiguredYear(t *testing.T) {
+	SetAcademicCalendar(&AcademicCalendar{Years: map[string]AcademicYearCalendar{}})
+	defer SetAcademicCalendar(nil)
+
+	_, err := BuildCalendar(nil, SemesterKey{Semester: SemesterSpring, Year: 2031}, time.Now())
+	if !errors.Is(err, ErrCalendarNotConfigured) {
+		t.Fatalf("error = %v, want ErrCalendarNotConfigured", err)
+	}
+	if !strings.Contains(err.Error(), "2030-2031") {
+		t.Errorf("error %q does not name the academic year", err)
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentAcademicYear returns the academic year that the given time falls in, e.g. "2024-2025".
+// DTU academic years start in August with the autumn semester.
+func CurrentAcademicYear(now time.Time) string {
+	start := now.Year()
+	if now.Month() < time.August {
+		start--
+	}
+	return fmt.Sprintf("%d-%d", start, start+1)
+}
+
+// SemesterKey identifies one semester of a given year, e.g. "Autumn 2025".
+type SemesterKey struct {
+	Semester Semester
+	Year     int
+}
+
+func (k SemesterKey) String() string {
+	return fmt.Sprintf("%s %d", k.Semester, k.Year)
+}
+
+// AcademicYear returns the academic year the semester belongs to, e.g. "2025-2026"
+// for both Autumn 2025 and Spring 2026.
+func (k SemesterKey) AcademicYear() string {
+	if k.Semester == SemesterSpring {
+		return fmt.Sprintf("%d-%d", k.Year-1, k.Year)
+	}
+	return fmt.Sprintf("%d-%d", k.Year, k.Year+1)
+}
+
+// CurrentSemester returns the semester the given time falls in.
+// January belongs to the autumn semester of the previous year, since the
+// 3-week January period finishes the autumn semester.
+func CurrentSemester(now time.Time) SemesterKey {
+	switch {
+	case now.Month() == time.January:
+		return SemesterKey{Semester: SemesterAutumn, Year: now.Year() - 1}
+	case now.Month() < time.August:
+		return SemesterKey{Semester: SemesterSpring, Year: now.Year()}
+	default:
+		return SemesterKey{Semester: SemesterAutumn, Year: now.Year()}
+	}
+}
+
+// semesterKeyPattern matches "Autumn 2025", "Forår 2026" and the short forms "E25" and "F26".
+var semesterKeyPattern = regexp.MustCompile(`(?i)^\s*(autumn|efterår|fall|spring|forår|e|f)\s*(\d{2}|\d{4})\s*$`)
+
+// ParseSemesterKey parses a semester such as "Autumn 2025", "Spring 2026", "E25" or "F26".
+func ParseSemesterKey(s string) (SemesterKey, error) {
+	m := semesterKeyPattern.FindStringSubmatch(s)
+	if m == nil {
+		return SemesterKey{}, fmt.Errorf("invalid semester %q, expected e.g. \"Autumn 2025\" or \"F26\"", s)
+	}
+
+	key := SemesterKey{Semester: SemesterSpring}
+	switch strings.ToLower(m[1]) {
+	case "autumn", "efterår", "fall", "e":
+		key.Semester = SemesterAutumn
+	}
+	key.Year, _ = strconv.Atoi(m[2])
+	if key.Year < 100 {
+		key.Year += 2000
+	}
+	return key, nil
+}
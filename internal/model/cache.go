@@ -0,0 +1,174 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+)
+
+// CourseCache stores parsed courses on disk, one JSON file per course and academic year.
+// Entries older than the TTL are still served, but are refreshed in the background.
+type CourseCache struct {
+	dir string
+	ttl time.Duration
+
+	mu         sync.Mutex
+	refreshing map[string]bool // keys currently being refreshed in the background
+}
+
+// NewCourseCache creates a cache storing its files under dir.
+func NewCourseCache(dir string, ttl time.Duration) *CourseCache {
+	return &CourseCache{
+		dir:        dir,
+		ttl:        ttl,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// path returns the file a course is stored in, e.g. "data/cache/2024-2025/02105.json".
+func (c *CourseCache) path(courseNumber, year string) (string, error) {
+	// The course number comes from user input, so make sure it cannot escape the cache directory.
+	if courseNumber == "" || filepath.Base(courseNumber) != courseNumber || filepath.Base(year) != year {
+		return "", fmt.Errorf("invalid cache key %q/%q", year, courseNumber)
+	}
+	return filepath.Join(c.dir, year, courseNumber+".json"), nil
+}
+
+// Get returns the cached course, or nil if it is not cached.
+// fresh reports whether the entry is younger than the TTL.
+func (c *CourseCache) Get(courseNumber, year string) (course *Course, fresh bool) {
+	path, err := c.path(courseNumber, year)
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("Error reading cached course:", err)
+		}
+		return nil, false
+	}
+
+	course = &Course{}
+	if err := json.Unmarshal(data, course); err != nil {
+		log.Printf("Ignoring corrupt cache entry %s: %v", path, err)
+		return nil, false
+	}
+	course.CourseAdditionalSection.Cached = true
+	// Entries written before courses carried their academic year are keyed by it all the same.
+	if course.AcademicYear == "" {
+		course.AcademicYear = year
+	}
+
+	return course, time.Since(course.CourseAdditionalSection.FetchTime) < c.ttl
+}
+
+// All returns every course cached for the academic year, fresh or not, in no particular order.
+func (c *CourseCache) All(year string) ([]*Course, error) {
+	if filepath.Base(year) != year {
+		return nil, fmt.Errorf("invalid cache year %q", year)
+	}
+	entries, err := os.ReadDir(filepath.Join(c.dir, year))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var courses []*Course
+	for _, entry := range entries {
+		courseNumber, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if course, _ := c.Get(courseNumber, year); course != nil {
+			courses = append(courses, course)
+		}
+	}
+	return courses, nil
+}
+
+// Put stores the course. The file is written atomically, so readers never see a partial entry.
+func (c *CourseCache) Put(course *Course, year string) error {
+	path, err := c.path(course.CourseNumber, year)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(course)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// RefreshInBackground re-fetches a stale entry without blocking the caller.
+// Only one refresh per course and year runs at a time.
+func (c *CourseCache) RefreshInBackground(courseNumber, year string, fetch func() (*Course, error)) {
+	key := year + "/" + courseNumber
+
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+
+		course, err := fetch()
+		if err != nil {
+			log.Printf("Background refresh of course %s failed: %v", key, err)
+			return
+		}
+		if err := c.Put(course, year); err != nil {
+			log.Printf("Failed to cache course %s: %v", key, err)
+		}
+	}()
+}
+
+var (
+	courseCacheMu sync.Mutex
+	courseCache   *CourseCache
+)
+
+// SetCourseCache overrides the cache used by FetchCourse.
+func SetCourseCache(cache *CourseCache) {
+	courseCacheMu.Lock()
+	defer courseCacheMu.Unlock()
+	courseCache = cache
+}
+
+// getCourseCache returns the configured cache, creating it from config.GlobalConfig on first use.
+func getCourseCache() *CourseCache {
+	courseCacheMu.Lock()
+	defer courseCacheMu.Unlock()
+
+	if courseCache == nil {
+		dir, ttl := "data/cache", 24*time.Hour
+		if config.GlobalConfig != nil {
+			dir, ttl = config.GlobalConfig.CourseCacheDir, config.GlobalConfig.CourseCacheTTL
+		}
+		courseCache = NewCourseCache(dir, ttl)
+	}
+	return courseCache
+}
+
+// KnownCourses returns every course of the current academic year that has been fetched before.
+func KnownCourses() ([]*Course, error) {
+	return getCourseCache().All(CurrentAcademicYear(time.Now()))
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cachedCourse returns a course as it would be cached after being fetched age ago.
+func cachedCourse(number, title string, age time.Duration) *Course {
+	course := &Course{CourseNumber: number, Title: title}
+	course.CourseAdditionalSection.FetchTime = time.Now().Add(-age)
+	return course
+}
+
+func TestCourseCacheGet(t *testing.T) {
+	cache := NewCourseCache(t.TempDir(), time.Hour)
+	for _, entry := range []struct {
+		course *Course
+		year   string
+	}{
+		{cachedCourse("02105", "Algorithms 1", time.Minute), "2025-2026"},
+		{cachedCourse("02105", "Algorithms and Data Structures 1", 2*time.Hour), "2024-2025"},
+		{cachedCourse("01017", "Discrete Mathematics", 2*time.Hour), "2025-2026"},
+	} {
+		if err := cache.Put(entry.course, entry.year); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		course    string
+		year      string
+		wantTitle string
+		wantFresh bool
+	}{
+		{"fresh hit", "02105", "2025-2026", "Algorithms 1", true},
+		{"each year is its own entry", "02105", "2024-2025", "Algorithms and Data Structures 1", false},
+		{"stale hit", "01017", "2025-2026", "Discrete Mathematics", false},
+		{"not cached", "01001", "2025-2026", "", false},
+		{"not cached for the year", "01017", "2024-2025", "", false},
+		{"course number escaping the cache", "../02105", "2025-2026", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course, fresh := cache.Get(tt.course, tt.year)
+			if tt.wantTitle == "" {
+				if course != nil {
+					t.Fatalf("Get = %q, want no course", course.Title)
+				}
+				return
+			}
+			if course == nil {
+				t.Fatal("Get = nil, want the cached course")
+			}
+			if course.Title != tt.wantTitle || fresh != tt.wantFresh {
+				t.Errorf("Get = %q (fresh %t), want %q (fresh %t)", course.Title, fresh, tt.wantTitle, tt.wantFresh)
+			}
+			if !course.CourseAdditionalSection.Cached || course.AcademicYear != tt.year {
+				t.Errorf("Get = cached %t, year %q, want a cached course of %s", course.CourseAdditionalSection.Cached, course.AcademicYear, tt.year)
+			}
+		})
+	}
+}
+
+func TestCourseCacheExpiry(t *testing.T) {
+	cache := NewCourseCache(t.TempDir(), time.Hour)
+	year := "2025-2026"
+	tests := []struct {
+		age       time.Duration
+		wantFresh bool
+	}{
+		{0, true},
+		{59 * time.Minute, true},
+		{61 * time.Minute, false},
+		{30 * 24 * time.Hour, false},
+	}
+	for _, tt := range tests {
+		if err := cache.Put(cachedCourse("02105", "Algorithms 1", tt.age), year); err != nil {
+			t.Fatal(err)
+		}
+		if course, fresh := cache.Get("02105", year); course == nil || fresh != tt.wantFresh {
+			t.Errorf("entry fetched %s ago: fresh = %t, want %t", tt.age, fresh, tt.wantFresh)
+		}
+	}
+}
+
+func TestCourseCacheIgnoresCorruptEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCourseCache(dir, time.Hour)
+	if err := os.MkdirAll(filepath.Join(dir, "2025-2026"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2025-2026", "02105.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if course, _ := cache.Get("02105", "2025-2026"); course != nil {
+		t.Errorf("Get = %+v, want a corrupt entry to be a miss", course)
+	}
+}
+
+func TestFetchCourseForYearRefreshesStaleEntries(t *testing.T) {
+	page, err := os.ReadFile("testdata/course_02105_en.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A past year, so the fetched course is not recorded in the reference index.
+	year := "2020-2021"
+	cache := NewCourseCache(t.TempDir(), time.Hour)
+	if err := cache.Put(cachedCourse("02105", "Old title", 2*time.Hour), year); err != nil {
+		t.Fatal(err)
+	}
+	source := &stubSource{name: "stub", html: string(page), release: make(chan struct{})}
+	SetCourseCache(cache)
+	SetCourseSource(source)
+	t.Cleanup(func() {
+		SetCourseCache(nil)
+		SetCourseSource(nil)
+	})
+
+	// Stale entries are served straight away, while a single refresh runs in the background.
+	for range 3 {
+		course, err := FetchCourseForYear("02105", year)
+		if err != nil {
+			t.Fatalf("FetchCourseForYear: %v", err)
+		}
+		if course.Title != "Old title" {
+			t.Errorf("FetchCourseForYear = %q, want the stale entry", course.Title)
+		}
+	}
+	close(source.release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if course, fresh := cache.Get("02105", year); fresh {
+			if course.Title != "Algorithms and Data Structures 1" {
+				t.Errorf("refreshed entry = %q, want the fetched course", course.Title)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the stale entry was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if source.calls != 1 {
+		t.Errorf("source called %d times, want a single refresh", source.calls)
+	}
+}
+
+func TestRefreshInBackgroundRunsOncePerKey(t *testing.T) {
+	cache := NewCourseCache(t.TempDir(), time.Hour)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fetch := func() (*Course, error) {
+		calls.Add(1)
+		<-release
+		return cachedCourse("02105", "Algorithms 1", 0), nil
+	}
+
+	cache.RefreshInBackground("02105", "2025-2026", fetch)
+	cache.RefreshInBackground("02105", "2025-2026", fetch)
+	// Another year is another key, refreshed on its own.
+	cache.RefreshInBackground("02105", "2024-2025", fetch)
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, fresh := cache.Get("02105", "2025-2026")
+		_, freshOld := cache.Get("02105", "2024-2025")
+		if fresh && freshOld {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the refreshes were not written to the cache")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("fetch called %d times, want once per key", got)
+	}
+}
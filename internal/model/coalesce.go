@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// flightCall is a fetch that is in progress or has just completed.
+type flightCall struct {
+	wg     sync.WaitGroup
+	course *Course
+	err    error
+	shared int // how many callers joined after the first
+}
+
+// flightGroup deduplicates concurrent fetches of the same key,
+// so a burst of requests for one course only renders the page once.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// Do runs fetch for the key, unless a fetch for it is already running,
+// in which case it waits for that one and returns its result.
+// The returned course is shared between all callers and must not be modified.
+func (g *flightGroup) Do(key string, fetch func() (*Course, error)) (course *Course, err error, coalesced bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		call.shared++
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.course, call.err, true
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.course, call.err = fetch()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	shared := call.shared
+	g.mu.Unlock()
+	call.wg.Done()
+
+	if shared > 0 {
+		log.Printf("Coalesced %d concurrent fetches of course %s", shared, key)
+	}
+	return call.course, call.err, false
+}
+
+// FetchMetrics counts how course fetches were served.
+type FetchMetrics struct {
+	// Requests is the number of fetches that reached the course source or joined one in progress.
+	Requests uint64
+	// Fetches is the number of fetches that actually hit the course source.
+	Fetches uint64
+	// Coalesced is the number of requests that reused another request's fetch.
+	Coalesced uint64
+}
+
+var (
+	courseFlights    flightGroup
+	requestCounter   atomic.Uint64
+	fetchCounter     atomic.Uint64
+	coalescedCounter atomic.Uint64
+)
+
+// String summarizes the metrics for the log, e.g. "12 requests, 9 fetches, 3 coalesced".
+func (m FetchMetrics) String() string {
+	return fmt.Sprintf("%d requests, %d fetches, %d coalesced", m.Requests, m.Fetches, m.Coalesced)
+}
+
+// GetFetchMetrics returns a snapshot of the fetch counters since startup.
+func GetFetchMetrics() FetchMetrics {
+	return FetchMetrics{
+		Requests:  requestCounter.Load(),
+		Fetches:   fetchCounter.Load(),
+		Coalesced: coalescedCounter.Load(),
+	}
+}
+
+// fetchCourseCoalesced fetches a course from the source, sharing the result
+// with any concurrent request for the same course and year.
+// Every course fetched from the source is recorded in the reference index.
+func fetchCourseCoalesced(courseNumber, year string) (*Course, error) {
+	requestCounter.Add(1)
+	course, err, coalesced := courseFlights.Do(year+"/"+courseNumber, func() (*Course, error) {
+		fetchCounter.Add(1)
+		course, err := fetchCourseFromSource(courseNumber, year)
+		if err == nil {
+			indexCourseReferences(course)
+		}
+		return course, err
+	})
+	if coalesced {
+		coalescedCounter.Add(1)
+	}
+	return course, err
+}
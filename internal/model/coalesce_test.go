@@ -0,0 +1,70 @@
+package model
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetchCourseCoalescedSharesOneFetch(t *testing.T) {
+	page, err := os.ReadFile("testdata/course_02105_en.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := &stubSource{name: "stub", html: string(page), release: make(chan struct{})}
+	SetCourseSource(source)
+	t.Cleanup(func() { SetCourseSource(nil) })
+
+	// A past year, so the fetched course is not recorded in the reference index.
+	const year, callers = "2020-2021", 5
+	before := GetFetchMetrics()
+
+	courses := make([]*Course, callers)
+	var wg sync.WaitGroup
+	for idx := range courses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			course, err := fetchCourseCoalesced("02105", year)
+			if err != nil {
+				t.Errorf("fetchCourseCoalesced: %v", err)
+			}
+			courses[idx] = course
+		}()
+	}
+
+	// Hold the fetch until every other caller has joined it.
+	deadline := time.Now().Add(5 * time.Second)
+	for joined := 0; joined < callers-1; {
+		if time.Now().After(deadline) {
+			t.Fatalf("only %d of %d callers joined the fetch", joined, callers-1)
+		}
+		time.Sleep(time.Millisecond)
+		courseFlights.mu.Lock()
+		if call := courseFlights.calls[year+"/02105"]; call != nil {
+			joined = call.shared
+		}
+		courseFlights.mu.Unlock()
+	}
+	close(source.release)
+	wg.Wait()
+
+	if source.calls != 1 {
+		t.Errorf("source called %d times, want 1", source.calls)
+	}
+	for idx, course := range courses {
+		if course != courses[0] {
+			t.Errorf("caller %d got a different course than caller 0", idx)
+		}
+	}
+	after := GetFetchMetrics()
+	got := FetchMetrics{
+		Requests:  after.Requests - before.Requests,
+		Fetches:   after.Fetches - before.Fetches,
+		Coalesced: after.Coalesced - before.Coalesced,
+	}
+	if want := (FetchMetrics{Requests: callers, Fetches: 1, Coalesced: callers - 1}); got != want {
+		t.Errorf("metrics went up by %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConflictKind tells what two courses clash on.
+type ConflictKind string
+
+const (
+	ConflictSchedule ConflictKind = "schedule"
+	ConflictExam     ConflictKind = "exam"
+)
+
+// Conflict is a clash between two courses, with one explanation per overlap.
+type Conflict struct {
+	A, B    *Course
+	Kind    ConflictKind
+	Reasons []string
+}
+
+// Overlaps reports whether two slots take place at the same time.
+// Slots without a known semester are assumed to overlap with either semester.
+func (s ScheduleSlot) Overlaps(other ScheduleSlot) bool {
+	if s.Semester != "" && other.Semester != "" && s.Semester != other.Semester {
+		return false
+	}
+	if s.Period != other.Period {
+		return false
+	}
+	if s.Period == Period3Week {
+		// 3-week courses are full time, so any two in the same period clash.
+		return s.Block == other.Block
+	}
+	return s.Weekday == other.Weekday && s.Start < other.End && other.Start < s.End
+}
+
+// ExamPlacement is the structured form of a course's "Date of examination" text.
+type ExamPlacement struct {
+	Raw string
+	// Codes are the block codes whose exam day the exam is placed on, e.g. "E3A".
+	Codes []string
+	// Dates are explicit exam dates, at midnight UTC.
+	Dates []time.Time
+}
+
+var (
+	// numericDatePattern matches dates such as "14/12/2025", "14-12-2025" and "14.12.2025".
+	numericDatePattern = regexp.MustCompile(`\b(\d{1,2})[./-](\d{1,2})[./-](\d{4})\b`)
+	// writtenDatePattern matches dates such as "14 December 2025" or "14. dec. 2025".
+	writtenDatePattern = regexp.MustCompile(`(?i)\b(\d{1,2})\.?\s+([a-zæøå]{3,9})\.?\s+(\d{4})\b`)
+)
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "maj": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "okt": time.October, "nov": time.November, "dec": time.December,
+}
+
+// ParseExamPlacement extracts the exam block codes and explicit dates from the exam text.
+func ParseExamPlacement(text string) ExamPlacement {
+	placement := ExamPlacement{Raw: text}
+	text = markdownLinkPattern.ReplaceAllString(text, "$1")
+
+	seenCodes := make(map[string]bool)
+	for _, m := range blockCodePattern.FindAllString(text, -1) {
+		if !seenCodes[m] {
+			seenCodes[m] = true
+			placement.Codes = append(placement.Codes, m)
+		}
+	}
+
+	seenDates := make(map[time.Time]bool)
+	addDate := func(day, month, year int) {
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		// time.Date normalizes invalid dates such as 31/02, which we skip.
+		if date.Day() != day || int(date.Month()) != month || seenDates[date] {
+			return
+		}
+		seenDates[date] = true
+		placement.Dates = append(placement.Dates, date)
+	}
+
+	for _, m := range numericDatePattern.FindAllStringSubmatch(text, -1) {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		addDate(day, month, year)
+	}
+	for _, m := range writtenDatePattern.FindAllStringSubmatch(text, -1) {
+		month, ok := monthNames[strings.ToLower(m[2])[:3]]
+		if !ok {
+			continue
+		}
+		day, _ := strconv.Atoi(m[1])
+		year, _ := strconv.Atoi(m[3])
+		addDate(day, int(month), year)
+	}
+
+	sort.Slice(placement.Dates, func(a, b int) bool {
+		return placement.Dates[a].Before(placement.Dates[b])
+	})
+	return placement
+}
+
+// FindConflicts compares every pair of courses and reports overlapping schedule blocks
+// and overlapping exams in the semester. Courses taught in both semesters only clash on
+// the blocks and exam days of the given one; an empty semester compares both.
+// Conflicts are returned in the order of the given courses.
+func FindConflicts(courses []*Course, semester Semester) []Conflict {
+	var conflicts []Conflict
+
+	for a := 0; a < len(courses); a++ {
+		for b := a + 1; b < len(courses); b++ {
+			if reasons := scheduleOverlaps(courses[a], courses[b], semester); len(reasons) > 0 {
+				conflicts = append(conflicts, Conflict{A: courses[a], B: courses[b], Kind: ConflictSchedule, Reasons: reasons})
+			}
+			if reasons := examOverlaps(courses[a], courses[b], semester); len(reasons) > 0 {
+				conflicts = append(conflicts, Conflict{A: courses[a], B: courses[b], Kind: ConflictExam, Reasons: reasons})
+			}
+		}
+	}
+	return conflicts
+}
+
+// scheduleOverlaps explains each pair of overlapping slots of the two courses in the semester.
+func scheduleOverlaps(a, b *Course, semester Semester) []string {
+	var reasons []string
+	seen := make(map[string]bool)
+
+	for _, slotA := range a.CourseScheduleSection.ParsedSchedule().Slots {
+		for _, slotB := range b.CourseScheduleSection.ParsedSchedule().Slots {
+			if !inSemester(slotA.Semester, semester) || !inSemester(slotB.Semester, semester) || !slotA.Overlaps(slotB) {
+				continue
+			}
+
+			var reason string
+			switch {
+			case slotA.Period == Period3Week:
+				reason = fmt.Sprintf("Both are full-time courses in the %s 3-week period", slotA.Block)
+			case slotA.Block == slotB.Block:
+				reason = fmt.Sprintf("Both are taught in %s (%s %s-%s)", slotA.Block,
+					slotA.Weekday, FormatClock(slotA.Start), FormatClock(slotA.End))
+			default:
+				reason = fmt.Sprintf("%s %s (%s) overlaps %s %s (%s)",
+					a.CourseNumber, slotA.Block, slotA.Weekday, b.CourseNumber, slotB.Block, slotB.Weekday)
+			}
+			if !seen[reason] {
+				seen[reason] = true
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	return reasons
+}
+
+// examOverlaps explains shared exam codes and exam dates of the two courses. Exam codes of
+// another semester than the given one are ignored.
+func examOverlaps(a, b *Course, semester Semester) []string {
+	var reasons []string
+	examA := ParseExamPlacement(a.CourseExamSection.DateOfExamination)
+	examB := ParseExamPlacement(b.CourseExamSection.DateOfExamination)
+
+	for _, codeA := range examA.Codes {
+		for _, codeB := range examB.Codes {
+			if !inSemester(codeSemester(codeA), semester) || !inSemester(codeSemester(codeB), semester) {
+				continue
+			}
+			switch {
+			case codeA == codeB:
+				reasons = append(reasons, fmt.Sprintf("Both exams are placed on the %s exam day", codeA))
+			case strings.HasPrefix(codeA, codeB) || strings.HasPrefix(codeB, codeA):
+				// "E3" is the whole block, so it covers the exam days of both E3A and E3B.
+				reasons = append(reasons, fmt.Sprintf("%s has its exam on %s and %s on %s, which may be the same day",
+					a.CourseNumber, codeA, b.CourseNumber, codeB))
+			}
+		}
+	}
+	for _, dateA := range examA.Dates {
+		for _, dateB := range examB.Dates {
+			if dateA.Equal(dateB) {
+				reasons = append(reasons, fmt.Sprintf("Both have an exam on %s", dateA.Format("Monday 2 January 2006")))
+			}
+		}
+	}
+	return reasons
+}
+
+// inSemester reports whether something in the given semester takes place in the wanted one.
+// An empty semester on either side matches both.
+func inSemester(semester, wanted Semester) bool {
+	return semester == "" || wanted == "" || semester == wanted
+}
+
+// codeSemester returns the semester of a block code, e.g. autumn for "E3A".
+func codeSemester(code string) Semester {
+	if strings.HasPrefix(code, "F") {
+		return SemesterSpring
+	}
+	return SemesterAutumn
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// conflictCourse is a course with the given schedule and exam texts.
+func conflictCourse(number, schedule, exam string) *Course {
+	course := &Course{CourseNumber: number}
+	course.CourseScheduleSection.Schedule = schedule
+	course.CourseExamSection.DateOfExamination = exam
+	return course
+}
+
+func TestFindConflicts(t *testing.T) {
+	type conflict struct {
+		kind    ConflictKind
+		reasons []string
+	}
+	tests := []struct {
+		name     string
+		a, b     *Course
+		semester Semester
+		want     []conflict
+	}{
+		{
+			name: "same block",
+			a:    conflictCourse("02105", "Autumn E3A", ""),
+			b:    conflictCourse("01017", "E3A", ""),
+			want: []conflict{{ConflictSchedule, []string{"Both are taught in E3A (Tuesday 08:00-12:00)"}}},
+		},
+		{
+			name: "whole block overlaps half a block",
+			a:    conflictCourse("02105", "E1", ""),
+			b:    conflictCourse("01017", "E1A", ""),
+			want: []conflict{{ConflictSchedule, []string{"Both are taught in E1A (Monday 08:00-12:00)"}}},
+		},
+		{
+			name: "different blocks",
+			a:    conflictCourse("02105", "E3A", ""),
+			b:    conflictCourse("01017", "E3B", ""),
+		},
+		{
+			name: "same block in different semesters",
+			a:    conflictCourse("02105", "E3A", ""),
+			b:    conflictCourse("01017", "F3A", ""),
+		},
+		{
+			name: "3-week period",
+			a:    conflictCourse("02105", "January", ""),
+			b:    conflictCourse("01017", "3-week period in January", ""),
+			want: []conflict{{ConflictSchedule, []string{"Both are full-time courses in the January 3-week period"}}},
+		},
+		{
+			name:     "course taught in both semesters only clashes in the asked one",
+			a:        conflictCourse("02105", "E3A and F3A", ""),
+			b:        conflictCourse("01017", "F3A", ""),
+			semester: SemesterAutumn,
+		},
+		{
+			name:     "clash in the asked semester",
+			a:        conflictCourse("02105", "E3A and F3A", ""),
+			b:        conflictCourse("01017", "F3A", ""),
+			semester: SemesterSpring,
+			want:     []conflict{{ConflictSchedule, []string{"Both are taught in F3A (Tuesday 08:00-12:00)"}}},
+		},
+		{
+			name: "same exam day",
+			a:    conflictCourse("02105", "", "E3A"),
+			b:    conflictCourse("01017", "", "Written exam, E3A"),
+			want: []conflict{{ConflictExam, []string{"Both exams are placed on the E3A exam day"}}},
+		},
+		{
+			name: "exam on the whole block",
+			a:    conflictCourse("02105", "", "E3"),
+			b:    conflictCourse("01017", "", "E3A"),
+			want: []conflict{{ConflictExam, []string{"02105 has its exam on E3 and 01017 on E3A, which may be the same day"}}},
+		},
+		{
+			name: "different exam days",
+			a:    conflictCourse("02105", "", "E3A"),
+			b:    conflictCourse("01017", "", "E3B"),
+		},
+		{
+			name:     "exam day of another semester",
+			a:        conflictCourse("02105", "", "F3A"),
+			b:        conflictCourse("01017", "", "F3A"),
+			semester: SemesterAutumn,
+		},
+		{
+			name: "same exam date",
+			a:    conflictCourse("02105", "", "14/12/2026"),
+			b:    conflictCourse("01017", "", "14 December 2026"),
+			want: []conflict{{ConflictExam, []string{"Both have an exam on Monday 14 December 2026"}}},
+		},
+		{
+			name: "schedule and exam",
+			a:    conflictCourse("02105", "E3A", "E3A"),
+			b:    conflictCourse("01017", "E3A", "E3A"),
+			want: []conflict{
+				{ConflictSchedule, []string{"Both are taught in E3A (Tuesday 08:00-12:00)"}},
+				{ConflictExam, []string{"Both exams are placed on the E3A exam day"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := FindConflicts([]*Course{tt.a, tt.b}, tt.semester)
+			if len(conflicts) != len(tt.want) {
+				t.Fatalf("FindConflicts = %+v, want %d conflict(s)", conflicts, len(tt.want))
+			}
+			for idx, got := range conflicts {
+				if got.A != tt.a || got.B != tt.b {
+					t.Errorf("conflict %d is between %s and %s, want the courses in order", idx, got.A.CourseNumber, got.B.CourseNumber)
+				}
+				if got.Kind != tt.want[idx].kind || !slices.Equal(got.Reasons, tt.want[idx].reasons) {
+					t.Errorf("conflict %d = %s %q, want %s %q", idx, got.Kind, got.Reasons, tt.want[idx].kind, tt.want[idx].reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestFindConflictsPerPair(t *testing.T) {
+	courses := []*Course{
+		conflictCourse("02105", "E3A", ""),
+		conflictCourse("01017", "E5A", ""),
+		conflictCourse("02101", "E3A", ""),
+		conflictCourse("01001", "E5A", ""),
+	}
+	var pairs []string
+	for _, conflict := range FindConflicts(courses, "") {
+		pairs = append(pairs, conflict.A.CourseNumber+"×"+conflict.B.CourseNumber)
+	}
+	if want := []string{"02105×02101", "01017×01001"}; !slices.Equal(pairs, want) {
+		t.Errorf("conflicting pairs = %q, want %q", pairs, want)
+	}
+}
+
+func TestParseExamPlacement(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		text      string
+		wantCodes []string
+		wantDates []time.Time
+	}{
+		{"E3A", []string{"E3A"}, nil},
+		{"[F4B](https://www.dtu.dk/exam) and F4B again", []string{"F4B"}, nil},
+		{"E3", []string{"E3"}, nil},
+		{"The exam is on 14/12/2026 and 3. jan. 2027", nil, []time.Time{date(2026, time.December, 14), date(2027, time.January, 3)}},
+		{"Eksamen 5 maj 2027", nil, []time.Time{date(2027, time.May, 5)}},
+		{"31/02/2027 is not a date", nil, nil},
+		{"Agreed upon with the teacher", nil, nil},
+	}
+	for _, tt := range tests {
+		got := ParseExamPlacement(tt.text)
+		if !slices.Equal(got.Codes, tt.wantCodes) || !slices.EqualFunc(got.Dates, tt.wantDates, time.Time.Equal) {
+			t.Errorf("ParseExamPlacement(%q) = %q, %v, want %q, %v", tt.text, got.Codes, got.Dates, tt.wantCodes, tt.wantDates)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package model
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
 )
 
@@ -107,6 +109,8 @@ type CourseAdditionalSection struct {
 	HomePage                  string
 	RegistrationSignUp        string
 	FetchTime                 time.Time
+	// Cached is set when the course was served from the cache rather than fetched just now.
+	Cached bool `json:"-"`
 }
 
 func (s CourseAdditionalSection) GetSectionName() string {
@@ -121,7 +125,11 @@ func (s CourseAdditionalSection) GetSectionValue() string {
 	sb.WriteString(utils.WriteLine("Department Involved", s.DepartmentInvolved))
 	sb.WriteString(utils.WriteLine("Home Page", s.HomePage))
 	sb.WriteString(utils.WriteLine("Registration Sign-Up", s.RegistrationSignUp))
-	sb.WriteString(utils.WriteLine("Fetched", s.FetchTime.Format(time.RFC1123)))
+	if s.Cached {
+		sb.WriteString(utils.WriteLine("Cached at", s.FetchTime.Format(time.RFC1123)))
+	} else {
+		sb.WriteString(utils.WriteLine("Fetched", s.FetchTime.Format(time.RFC1123)))
+	}
 	return sb.String()
 }
 
@@ -138,9 +146,86 @@ type CourseTypeSection struct {
 	CourseType []utils.CourseTypeBlock
 }
 
+// CourseObjectivesSection contains the general course objectives.
+type CourseObjectivesSection struct {
+	GeneralObjectives string
+}
+
+func (s CourseObjectivesSection) GetSectionName() string {
+	return "General Course Objectives"
+}
+
+func (s CourseObjectivesSection) GetSectionValue() string {
+	return utils.Truncate(s.GeneralObjectives, utils.MaxFieldValueLength)
+}
+
+func (s CourseObjectivesSection) GetSectionInline() bool {
+	return false
+}
+
+// SetInLine implements utils.Section.
+func (s CourseObjectivesSection) SetInLine(IsInLine bool) {
+	panic("CourseObjectivesSection does not support inline formatting")
+}
+
+// CourseLearningObjectivesSection contains the learning objectives as a list.
+type CourseLearningObjectivesSection struct {
+	// Intro is the sentence before the list, e.g. "A student who has met the objectives of the course will be able to:"
+	Intro      string
+	Objectives []string
+}
+
+func (s CourseLearningObjectivesSection) GetSectionName() string {
+	return "Learning Objectives"
+}
+
+func (s CourseLearningObjectivesSection) GetSectionValue() string {
+	var sb strings.Builder
+	if s.Intro != "" {
+		sb.WriteString(s.Intro + "\n")
+	}
+	for _, objective := range s.Objectives {
+		sb.WriteString("• " + objective + "\n")
+	}
+	return utils.Truncate(sb.String(), utils.MaxFieldValueLength)
+}
+
+func (s CourseLearningObjectivesSection) GetSectionInline() bool {
+	return false
+}
+
+// SetInLine implements utils.Section.
+func (s CourseLearningObjectivesSection) SetInLine(IsInLine bool) {
+	panic("CourseLearningObjectivesSection does not support inline formatting")
+}
+
+// CourseContentSection contains the description of the course content.
+type CourseContentSection struct {
+	Content string
+}
+
+func (s CourseContentSection) GetSectionName() string {
+	return "Content"
+}
+
+func (s CourseContentSection) GetSectionValue() string {
+	return utils.Truncate(s.Content, utils.MaxFieldValueLength)
+}
+
+func (s CourseContentSection) GetSectionInline() bool {
+	return false
+}
+
+// SetInLine implements utils.Section.
+func (s CourseContentSection) SetInLine(IsInLine bool) {
+	panic("CourseContentSection does not support inline formatting")
+}
+
 // Course represents the course details.
 type Course struct {
-	CourseNumber             string
+	CourseNumber string
+	// AcademicYear is the catalogue version of the course, e.g. "2025-2026".
+	AcademicYear             string
 	Title                    string
 	DanishTitle              string
 	LanguageOfInstruction    string
@@ -150,7 +235,11 @@ type Course struct {
 	CourseExamSection        CourseExamSection
 	CourseResponsibleSection CourseResponsibleSection
 	CourseAdditionalSection  CourseAdditionalSection
-	IsInLine                 bool // New field for inline formatting
+	// The description blocks below the information table.
+	CourseObjectivesSection         CourseObjectivesSection
+	CourseLearningObjectivesSection CourseLearningObjectivesSection
+	CourseContentSection            CourseContentSection
+	IsInLine                        bool // New field for inline formatting
 }
 
 // GetSectionName returns a formatted section header for Discord
@@ -164,6 +253,7 @@ func (c *Course) GetSectionValue() string {
 
 	// Always show the Title as a header.
 	sb.WriteString(utils.WriteLine("Danish Title", c.DanishTitle))
+	sb.WriteString(utils.WriteLine("Academic Year", c.AcademicYear))
 	sb.WriteString(utils.WriteLine("Language", c.LanguageOfInstruction))
 	sb.WriteString(utils.WriteLine("ECTS", c.ECTS))
 
@@ -180,114 +270,118 @@ func (c *Course) SetInLine(isInLine bool) {
 	c.IsInLine = isInLine
 }
 
-// FetchCourse retrieves the *rendered* DTU course page and parses it into a Course struct.
-func FetchCourse(courseNumber string) (*Course, error) {
-	// Build the course URL
-	url := fmt.Sprintf("https://kurser.dtu.dk/course/%s", courseNumber)
-
-	// Use the dynamic fetcher from utils
-	doc, err := utils.FetchDynamicCoursePage(url)
-	if err != nil {
-		return nil, err
+// DescriptionSections returns the non-empty description sections, in page order.
+// Discord rejects embed fields without a value, so empty sections are left out.
+func (c *Course) DescriptionSections() []utils.Section {
+	var sections []utils.Section
+	if c.CourseObjectivesSection.GeneralObjectives != "" {
+		sections = append(sections, c.CourseObjectivesSection)
 	}
-
-	// Check if the main info is actually present
-	if doc.Find("div.box.information").Length() == 0 {
-		log.Println("No course information found on the rendered page!")
-		return nil, nil
+	if len(c.CourseLearningObjectivesSection.Objectives) > 0 || c.CourseLearningObjectivesSection.Intro != "" {
+		sections = append(sections, c.CourseLearningObjectivesSection)
 	}
+	if c.CourseContentSection.Content != "" {
+		sections = append(sections, c.CourseContentSection)
+	}
+	return sections
+}
 
-	// Create a new Course object
-	course := &Course{
-		CourseNumber: courseNumber,
-		CourseAdditionalSection: CourseAdditionalSection{
-			FetchTime: time.Now(),
-		},
+// CatalogueBaseURL returns the configured root of the course catalogue without a trailing slash.
+func CatalogueBaseURL() string {
+	if config.GlobalConfig != nil && config.GlobalConfig.CatalogueBaseURL != "" {
+		return config.GlobalConfig.CatalogueBaseURL
 	}
+	return config.DefaultCatalogueBaseURL
+}
 
-	// Parse the course details
-	rawTitle := utils.ExtractText(doc, "div.col-xs-8 h2", "")
-	// We remove the leading course number from the title e.g., "10060 Physics (Polytechnical Foundation) -> Physics (Polytechnical Foundation)"
-	course.Title = strings.TrimSpace(strings.SplitN(rawTitle, " ", 2)[1])
-	course.DanishTitle = utils.ExtractFieldByName(doc, "Danish title")
-	course.LanguageOfInstruction = utils.ExtractFieldByName(doc, "Language of instruction")
-	course.ECTS = utils.ExtractFieldByName(doc, "Point( ECTS )")
-	course.CourseTypeSection.CourseType = utils.ExtractCourseTypeAdvanced(doc)
-	course.CourseScheduleSection.Schedule = utils.ExtractFieldByName(doc, "Schedule")
-	course.CourseScheduleSection.Location = utils.ExtractFieldByName(doc, "Location")
-	course.CourseScheduleSection.ScopeAndForm = utils.ExtractFieldByName(doc, "Scope and form")
-	course.CourseScheduleSection.DurationOfCourse = utils.ExtractFieldByName(doc, "Duration of course")
-	course.CourseExamSection.DateOfExamination = utils.ExtractFieldByName(doc, "Date of examination")
-	course.CourseExamSection.TypeOfAssessment = utils.ExtractFieldByName(doc, "Type of assessment")
-	course.CourseExamSection.ExamDuration = utils.ExtractFieldByName(doc, "Exam duration")
-	course.CourseExamSection.Aid = utils.ExtractFieldByName(doc, "Aid")
-	course.CourseExamSection.Evaluation = utils.ExtractFieldByName(doc, "Evaluation")
-	course.CourseAdditionalSection.NotApplicableTogetherWith = utils.ExtractFieldByName(doc, "Not applicable together with")
-	course.CourseAdditionalSection.AcademicPrerequisites = utils.ExtractFieldByName(doc, "Academic prerequisites")
-	course.CourseResponsibleSection.Responsible = utils.ExtractFieldByName(doc, "Responsible")
-	course.CourseResponsibleSection.CourseCoResponsible = utils.ExtractFieldByName(doc, "Course co-responsible")
-	course.CourseAdditionalSection.Department = utils.ExtractFieldByName(doc, "Department")
-	course.CourseAdditionalSection.DepartmentInvolved = utils.ExtractFieldByName(doc, "Department involved")
-	course.CourseAdditionalSection.HomePage = utils.ExtractFieldByName(doc, "Home page")
-	course.CourseAdditionalSection.RegistrationSignUp = utils.ExtractFieldByName(doc, "Registration sign-up")
-
-	// Validate that we actually found some data
-	if course.Title == "" && course.DanishTitle == "" && course.ECTS == "" {
-		log.Println("No valid data received for course:", courseNumber)
-		return nil, nil
+// CourseURL returns the catalogue page of the given course in the academic year, e.g. "2025-2026".
+// An empty year gives the page of the version the catalogue shows by default.
+func CourseURL(courseNumber, year string) string {
+	if year == "" {
+		return fmt.Sprintf("%s/course/%s", CatalogueBaseURL(), courseNumber)
 	}
+	return fmt.Sprintf("%s/course/%s/%s", CatalogueBaseURL(), year, courseNumber)
+}
 
-	return course, nil
+// FetchCourse returns the course for the current academic year.
+// Errors are always a *FetchError, see errors.go for the possible kinds.
+func FetchCourse(courseNumber string) (*Course, error) {
+	return FetchCourseForYear(courseNumber, CurrentAcademicYear(time.Now()))
 }
 
-// SaveCourseToFile appends the given course string (e.g., "01001, Mathematics 1a") to "data/courses.txt"
-func SaveCourseToFile(course string) error {
-	// Read the existing file (if it exists) to check for duplicates.
-	data, err := os.ReadFile("data/courses.txt")
-	if err == nil {
-		// Split the file content into lines and check each one.
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) == course {
-				// Course already exists; nothing to do.
-				return nil
-			}
+// FetchCourseForYear returns the version of the course for the academic year, e.g. "2026-2027".
+// Cached courses are returned straight away; stale ones are refreshed in the background.
+// The course number is matched ignoring case and surrounding spaces, so "4210x" and "4210X"
+// share a cache entry. Errors are always a *FetchError, see errors.go for the possible kinds.
+func FetchCourseForYear(courseNumber, year string) (*Course, error) {
+	courseNumber = strings.ToUpper(strings.TrimSpace(courseNumber))
+	if err := ValidateCourseNumber(courseNumber); err != nil {
+		return nil, err
+	}
+	if err := ValidateAcademicYear(courseNumber, year); err != nil {
+		return nil, err
+	}
+
+	cache := getCourseCache()
+
+	if cached, fresh := cache.Get(courseNumber, year); cached != nil {
+		if !fresh {
+			cache.RefreshInBackground(courseNumber, year, func() (*Course, error) {
+				return fetchCourseCoalesced(courseNumber, year)
+			})
 		}
-	} else if !os.IsNotExist(err) {
-		// An error other than file not existing occurred.
-		return err
+		return cached, nil
 	}
 
-	// Open file in append mode; create it if it doesn't exist.
-	f, err := os.OpenFile("data/courses.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// Concurrent requests for the same course share a single fetch.
+	course, err := fetchCourseCoalesced(courseNumber, year)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := cache.Put(course, year); err != nil {
+		log.Println("Failed to cache course:", err)
 	}
-	defer f.Close()
+	return course, nil
+}
 
-	// Write the course string followed by a newline.
-	if _, err = f.WriteString(course + "\n"); err != nil {
-		return err
+// RefreshCourse fetches the current version of the course from the source, bypassing the cache,
+// and stores the result in the cache.
+func RefreshCourse(courseNumber string) (*Course, error) {
+	if err := ValidateCourseNumber(courseNumber); err != nil {
+		return nil, err
 	}
 
-	return nil
+	year := CurrentAcademicYear(time.Now())
+	course, err := fetchCourseCoalesced(courseNumber, year)
+	if err != nil {
+		return nil, err
+	}
+	if err := getCourseCache().Put(course, year); err != nil {
+		log.Println("Failed to cache course:", err)
+	}
+	return course, nil
 }
 
-// GetSavedCourses reads "data/courses.txt" and returns a slice of non-empty course lines.
-func GetSavedCourses() ([]string, error) {
-	data, err := os.ReadFile("data/courses.txt")
+// fetchCourseFromSource retrieves the *rendered* DTU course page of the academic year
+// and parses it into a Course struct.
+func fetchCourseFromSource(courseNumber, year string) (*Course, error) {
+	// Build the course URL
+	url := CourseURL(courseNumber, year)
+
+	// Use the configured course source (chromedp or plain HTTP)
+	doc, err := getCourseSource().FetchPage(context.Background(), url)
 	if err != nil {
-		return nil, err
+		return nil, classifySourceError(courseNumber, year, err)
 	}
 
-	// Split the file content by newline.
-	lines := strings.Split(string(data), "\n")
-	var courses []string
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" {
-			courses = append(courses, trimmed)
-		}
+	course, err := ParseCourseDocument(doc, courseNumber)
+	if errors.Is(err, ErrNoCourseInformation) {
+		log.Printf("No valid data received for course %s in %s", courseNumber, year)
+		return nil, &FetchError{CourseNumber: courseNumber, AcademicYear: year, Kind: ErrCourseNotFound, Err: err}
+	}
+	if err != nil {
+		return nil, &FetchError{CourseNumber: courseNumber, AcademicYear: year, Kind: ErrParseFailure, Err: err}
 	}
-	return courses, nil
+	course.AcademicYear = year
+	return course, nil
 }
@@ -0,0 +1,199 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores of the ways a query can match a course. Higher is better.
+const (
+	scoreNumberExact  = 1000
+	scoreNumberPrefix = 900
+	scoreTitlePrefix  = 800
+	scoreWordPrefix   = 700
+	scoreSubstring    = 600
+	// scoreWords is the score of a query whose words all match words of a title,
+	// less the typo penalty for each edit needed.
+	scoreWords   = 500
+	typoPenalty  = 50
+	maxTypoEdits = 2
+)
+
+// courseSearchDoc is a course prepared for searching: its preferred entry and its normalized texts.
+type courseSearchDoc struct {
+	entry  CourseIndexEntry
+	number string
+	// titles are the normalized English and Danish titles, with their words.
+	titles []string
+	words  [][]string
+}
+
+// foldedLetters spells out the Danish letters the way they are written without them,
+// and strips the accents most often found in course titles.
+var foldedLetters = strings.NewReplacer(
+	"æ", "ae", "ø", "oe", "å", "aa",
+	"ä", "ae", "ö", "oe", "ü", "ue",
+	"é", "e", "è", "e", "ê", "e", "á", "a", "à", "a", "í", "i", "ó", "o", "ú", "u",
+)
+
+// normalizeSearchText lowercases the text, folds special letters and replaces punctuation with spaces,
+// so "Matematik 1a" and "MATEMATIK-1A" are searched alike and "Forår" matches "foraar".
+func normalizeSearchText(text string) string {
+	text = foldedLetters.Replace(strings.ToLower(text))
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func newCourseSearchDoc(entry CourseIndexEntry) courseSearchDoc {
+	doc := courseSearchDoc{entry: entry, number: strings.ToLower(entry.Number)}
+	for _, title := range []string{entry.Title, entry.DanishTitle} {
+		if title = normalizeSearchText(title); title != "" {
+			doc.titles = append(doc.titles, title)
+			doc.words = append(doc.words, strings.Fields(title))
+		}
+	}
+	return doc
+}
+
+// score rates how well the normalized query matches the course. Zero means no match.
+func (doc courseSearchDoc) score(query string) int {
+	number := strings.ReplaceAll(query, " ", "")
+	switch {
+	case number == doc.number:
+		return scoreNumberExact
+	case strings.HasPrefix(doc.number, number):
+		return scoreNumberPrefix
+	}
+
+	best := 0
+	for idx, title := range doc.titles {
+		var score int
+		switch {
+		case strings.HasPrefix(title, query):
+			score = scoreTitlePrefix
+		case strings.Contains(" "+title, " "+query):
+			score = scoreWordPrefix
+		case strings.Contains(title, query):
+			score = scoreSubstring
+		default:
+			score = matchWords(strings.Fields(query), doc.words[idx])
+		}
+		best = max(best, score)
+	}
+	return best
+}
+
+// matchWords scores a query whose every word is close to some word of the title.
+// The last query word may be unfinished, so it is also compared to the start of the title words.
+func matchWords(queryWords, titleWords []string) int {
+	if len(queryWords) == 0 {
+		return 0
+	}
+	score := scoreWords
+	for idx, queryWord := range queryWords {
+		last := idx == len(queryWords)-1
+		allowed := allowedTypos(queryWord)
+		bestEdits := allowed + 1
+		for _, titleWord := range titleWords {
+			edits := levenshtein(queryWord, titleWord, allowed)
+			if runes := []rune(titleWord); last && len(runes) > len([]rune(queryWord)) {
+				edits = min(edits, levenshtein(queryWord, string(runes[:len([]rune(queryWord))]), allowed))
+			}
+			bestEdits = min(bestEdits, edits)
+		}
+		if bestEdits > allowed {
+			return 0
+		}
+		score -= bestEdits * typoPenalty
+	}
+	return score
+}
+
+// allowedTypos is the number of edits tolerated in a query word. Short words must match exactly,
+// since almost every short word is a single edit away from some title word.
+func allowedTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return maxTypoEdits
+	}
+}
+
+// levenshtein returns the edit distance between a and b, or limit+1 if it exceeds limit.
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return min(prev[len(rb)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Search returns at most limit courses matching the query, best match first. The query is
+// matched against the course number and the English and Danish titles, tolerating typos,
+// case and the spelling of æ, ø and å. An empty query returns the first courses by number.
+func (s *CourseStore) Search(query string, limit int) []CourseIndexEntry {
+	query = normalizeSearchText(query)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type match struct {
+		doc   *courseSearchDoc
+		score int
+	}
+	var matches []match
+	for idx := range s.search {
+		doc := &s.search[idx]
+		if query == "" {
+			matches = append(matches, match{doc, 0})
+		} else if score := doc.score(query); score > 0 {
+			matches = append(matches, match{doc, score})
+		}
+	}
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].score != matches[b].score {
+			return matches[a].score > matches[b].score
+		}
+		return matches[a].doc.entry.Number < matches[b].doc.entry.Number
+	})
+
+	results := make([]CourseIndexEntry, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		results = append(results, m.doc.entry)
+	}
+	return results
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+// testSearchStore is an in-memory store with a few courses whose titles exercise the search.
+func testSearchStore() *CourseStore {
+	store := &CourseStore{}
+	store.setEntriesLocked([]CourseIndexEntry{
+		{Number: "01001", Title: "Mathematics 1a", DanishTitle: "Matematik 1a"},
+		{Number: "01017", Title: "Discrete Mathematics", DanishTitle: "Diskret matematik"},
+		{Number: "02105", Title: "Algorithms and Data Structures 1", DanishTitle: "Algoritmer og datastrukturer 1"},
+		{Number: "10020", Title: "Physics in Spring", DanishTitle: "Forår i fysik"},
+		{Number: "42000", Title: "Introduction to Economics", DanishTitle: "Introduktion til økonomi"},
+	})
+	return store
+}
+
+func TestCourseStoreSearch(t *testing.T) {
+	store := testSearchStore()
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"English title word", "discrete", []string{"01017"}},
+		{"Danish title word", "matematik", []string{"01001", "01017"}},
+		{"title prefix ranks above word prefix", "math", []string{"01001", "01017"}},
+		{"number prefix", "01", []string{"01001", "01017"}},
+		{"exact number", "01017", []string{"01017"}},
+		{"case and punctuation", "MATHEMATICS-1A", []string{"01001"}},
+		{"one typo", "algoritms", []string{"02105"}},
+		{"two typos in a long word", "dicsrete", []string{"01017"}},
+		{"one typo in a short word", "dskret", []string{"01017"}},
+		{"too many typos for the word length", "dskrte", nil},
+		{"short words must match exactly", "mth", nil},
+		{"unfinished last word", "structures algo", []string{"02105"}},
+		{"only the last word may be unfinished", "algo structures", nil},
+		{"å spelled aa", "foraar", []string{"10020"}},
+		{"å spelled a", "forar", []string{"10020"}},
+		{"ø spelled o", "okonomi", []string{"42000"}},
+		{"capital Ø", "ØKONOMI", []string{"42000"}},
+		{"no match", "xyz", nil},
+		{"empty query lists everything by number", "", []string{"01001", "01017", "02105", "10020", "42000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range store.Search(tt.query, 10) {
+				got = append(got, entry.Number)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourseStoreSearchLimit(t *testing.T) {
+	got := testSearchStore().Search("01", 1)
+	if len(got) != 1 || got[0].Number != "01001" {
+		t.Errorf("Search(01, 1) = %v, want only 01001", got)
+	}
+}
+
+func TestCourseSearchDocScore(t *testing.T) {
+	doc := newCourseSearchDoc(CourseIndexEntry{Number: "02105", Title: "Algorithms and Data Structures 1"})
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"02105", scoreNumberExact},
+		{"021", scoreNumberPrefix},
+		{"algorithms and", scoreTitlePrefix},
+		{"data", scoreWordPrefix},
+		{"ucture", scoreSubstring},
+		{"structures algorithms", scoreWords},
+		{"structures algoritms", scoreWords - typoPenalty},
+		{"strctures algoritms", scoreWords - 2*typoPenalty},
+		{"graphs", 0},
+	}
+	for _, tt := range tests {
+		if got := doc.score(normalizeSearchText(tt.query)); got != tt.want {
+			t.Errorf("score(%q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"algorithms", "algorithms", 2, 0},
+		{"foraar", "forar", 1, 1},
+		{"kitten", "sitting", 3, 3},
+		// Past the limit, the distance is cut off at limit+1.
+		{"kitten", "sitting", 2, 3},
+		{"kitten", "sitting", 0, 1},
+		// Lengths too far apart are cut off before comparing.
+		{"abc", "abcdef", 1, 2},
+		{"", "ab", 2, 2},
+		{"øre", "ore", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("levenshtein(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+)
+
+// legacyCoursesFile is the flat "number, title" list the course store replaced.
+// Courses in it that the store does not know yet are merged in whenever the list changes.
+const legacyCoursesFile = "data/courses.txt"
+
+// CourseIndexEntry is the metadata the course store keeps of one version of a course.
+type CourseIndexEntry struct {
+	Number      string   `json:"number"`
+	Title       string   `json:"title"`
+	DanishTitle string   `json:"danishTitle,omitempty"`
+	ECTS        string   `json:"ects,omitempty"`
+	Department  string   `json:"department,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Schedule    string   `json:"schedule,omitempty"`
+	Teachers    []string `json:"teachers,omitempty"`
+	// AcademicYear is empty for entries migrated from the flat course list.
+	AcademicYear string    `json:"academicYear,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// String formats the entry as shown to users, e.g. "01001, Mathematics 1a".
+func (e CourseIndexEntry) String() string {
+	return fmt.Sprintf("%s, %s", e.Number, e.Title)
+}
+
+// NewCourseIndexEntry summarizes the course for the store. Markdown links are reduced to their text.
+func NewCourseIndexEntry(course *Course) CourseIndexEntry {
+	plain := func(s string) string {
+		return strings.TrimSpace(markdownLinkPattern.ReplaceAllString(s, "$1"))
+	}
+
+	var teachers []string
+	for _, teacher := range []string{course.CourseResponsibleSection.Responsible, course.CourseResponsibleSection.CourseCoResponsible} {
+		if teacher = plain(teacher); teacher != "" {
+			teachers = append(teachers, teacher)
+		}
+	}
+	return CourseIndexEntry{
+		Number:       course.CourseNumber,
+		Title:        course.Title,
+		DanishTitle:  course.DanishTitle,
+		ECTS:         course.ECTS,
+		Department:   plain(course.CourseAdditionalSection.Department),
+		Language:     plain(course.LanguageOfInstruction),
+		Schedule:     plain(course.CourseScheduleSection.Schedule),
+		Teachers:     teachers,
+		AcademicYear: course.AcademicYear,
+		UpdatedAt:    course.CourseAdditionalSection.FetchTime,
+	}
+}
+
+// courseKey identifies one version of a course.
+type courseKey struct {
+	number, year string
+}
+
+// CourseStore holds the metadata of every known course, one version per course and academic year.
+// It is persisted as a JSON-lines file that is rewritten atomically on every update, and served
+// from memory. Updates from other processes, such as a crawl started from the command line,
+// are merged in before writing, and every read-merge-write holds an advisory lock on the
+// file "<path>.lock", so two processes never overwrite each other's changes.
+type CourseStore struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[courseKey]CourseIndexEntry
+	// byNumber lists the versions of each course, newest academic year first.
+	byNumber map[string][]courseKey
+	// search holds the preferred version of every course, prepared for Search.
+	search []courseSearchDoc
+	// modTime is the modification time of the file when it was last read or written.
+	modTime time.Time
+}
+
+// OpenCourseStore loads the store at path, creating it if it does not exist yet, and merges
+// in the courses of "data/courses.txt" it does not know.
+func OpenCourseStore(path string) (*CourseStore, error) {
+	return openCourseStore(path, legacyCoursesFile)
+}
+
+func openCourseStore(path, legacyPath string) (*CourseStore, error) {
+	store := &CourseStore{path: path}
+
+	unlock, err := lockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, modTime, err := readCourseStoreFile(path)
+	if err != nil {
+		return nil, err
+	}
+	store.setEntriesLocked(entries)
+	store.modTime = modTime
+
+	if err := store.mergeLegacyCoursesLocked(legacyPath); err != nil {
+		return nil, fmt.Errorf("migrating %s: %w", legacyPath, err)
+	}
+	return store, nil
+}
+
+// mergeLegacyCoursesLocked adds the courses of the flat course list that the store does not
+// know yet. The marker file "<path>.migrated" holds the checksum of the list last merged, so
+// the list is only merged again when it changes. s.mu and the file lock must be held.
+func (s *CourseStore) mergeLegacyCoursesLocked(legacyPath string) error {
+	data, err := os.ReadFile(legacyPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	marker := s.path + ".migrated"
+	if merged, err := os.ReadFile(marker); err == nil && strings.TrimSpace(string(merged)) == checksum {
+		return nil
+	}
+
+	added := 0
+	for _, entry := range parseLegacyCourses(data) {
+		// Known courses already have better data than the list.
+		if _, known := s.byNumber[entry.Number]; known {
+			continue
+		}
+		s.entries[courseKey{entry.Number, entry.AcademicYear}] = entry
+		added++
+	}
+	if added > 0 {
+		log.Printf("Migrating %d courses from %s to %s", added, legacyPath, s.path)
+		s.setEntriesLocked(mapValues(s.entries))
+		if err := s.saveLocked(); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(marker, []byte(checksum+"\n"))
+}
+
+// Put stores the courses, replacing earlier data of the same course and academic year.
+func (s *CourseStore) Put(courses ...*Course) error {
+	entries := make([]CourseIndexEntry, len(courses))
+	for idx, course := range courses {
+		entries[idx] = NewCourseIndexEntry(course)
+	}
+	return s.PutEntries(entries...)
+}
+
+// PutEntries stores the entries, replacing earlier data of the same course and academic year.
+// The file is only rewritten if anything changed.
+func (s *CourseStore) PutEntries(entries ...CourseIndexEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Hold the file lock from reading the file until the merged store is written, so a change
+	// another process makes in between is not lost.
+	unlock, err := lockFile(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// The file is read even if its modification time is unchanged, as two writes can share one.
+	if err := s.mergeFileLocked(); err != nil {
+		log.Printf("Ignoring unreadable course store %s: %v", s.path, err)
+	}
+
+	all := s.entries
+	changed := false
+	for _, entry := range entries {
+		key := courseKey{entry.Number, entry.AcademicYear}
+		if existing, ok := all[key]; ok && sameEntry(existing, entry) {
+			continue
+		}
+		all[key] = entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	s.setEntriesLocked(mapValues(all))
+	return s.saveLocked()
+}
+
+// sameEntry reports whether two entries hold the same data, ignoring when they were fetched.
+func sameEntry(a, b CourseIndexEntry) bool {
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return bytes.Equal(aJSON, bJSON)
+}
+
+// Reload merges in changes another process has written to the file, such as a crawl
+// started from the command line.
+func (s *CourseStore) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reloadIfChangedLocked()
+}
+
+// Len returns the number of distinct courses in the store.
+func (s *CourseStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byNumber)
+}
+
+// Get returns the preferred version of the course: the current academic year if it is known,
+// otherwise the newest one.
+func (s *CourseStore) Get(number string) (CourseIndexEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.preferredLocked(strings.ToUpper(number))
+}
+
+// GetYear returns the version of the course for the academic year.
+func (s *CourseStore) GetYear(number, year string) (CourseIndexEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[courseKey{strings.ToUpper(number), year}]
+	return entry, ok
+}
+
+// All returns the preferred version of every course, sorted by number.
+func (s *CourseStore) All() []CourseIndexEntry {
+	return s.find(func(CourseIndexEntry) bool { return true })
+}
+
+// FindByTitle returns the courses whose English or Danish title contains the query, ignoring case.
+func (s *CourseStore) FindByTitle(query string) []CourseIndexEntry {
+	query = strings.ToLower(query)
+	return s.find(func(entry CourseIndexEntry) bool {
+		return strings.Contains(strings.ToLower(entry.Title), query) ||
+			strings.Contains(strings.ToLower(entry.DanishTitle), query)
+	})
+}
+
+// FindByDepartment returns the courses whose department contains the query, e.g. "01" or "Compute".
+func (s *CourseStore) FindByDepartment(query string) []CourseIndexEntry {
+	query = strings.ToLower(query)
+	return s.find(func(entry CourseIndexEntry) bool {
+		return strings.Contains(strings.ToLower(entry.Department), query)
+	})
+}
+
+// FindByTeacher returns the courses where a responsible or co-responsible teacher matches the query.
+func (s *CourseStore) FindByTeacher(query string) []CourseIndexEntry {
+	query = strings.ToLower(query)
+	return s.find(func(entry CourseIndexEntry) bool {
+		for _, teacher := range entry.Teachers {
+			if strings.Contains(strings.ToLower(teacher), query) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// find returns the preferred version of the courses matching the predicate, sorted by number.
+func (s *CourseStore) find(match func(CourseIndexEntry) bool) []CourseIndexEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found []CourseIndexEntry
+	for number := range s.byNumber {
+		if entry, ok := s.preferredLocked(number); ok && match(entry) {
+			found = append(found, entry)
+		}
+	}
+	sort.Slice(found, func(a, b int) bool {
+		return found[a].Number < found[b].Number
+	})
+	return found
+}
+
+// preferredLocked returns the version of the course Get returns. s.mu must be held.
+func (s *CourseStore) preferredLocked(number string) (CourseIndexEntry, bool) {
+	keys := s.byNumber[number]
+	if len(keys) == 0 {
+		return CourseIndexEntry{}, false
+	}
+	if entry, ok := s.entries[courseKey{number, CurrentAcademicYear(time.Now())}]; ok {
+		return entry, true
+	}
+	return s.entries[keys[0]], true
+}
+
+// setEntriesLocked replaces the contents of the store and rebuilds the lookup by number.
+// s.mu must be held for writing.
+func (s *CourseStore) setEntriesLocked(entries []CourseIndexEntry) {
+	s.entries = make(map[courseKey]CourseIndexEntry, len(entries))
+	s.byNumber = make(map[string][]courseKey)
+	for _, entry := range entries {
+		key := courseKey{entry.Number, entry.AcademicYear}
+		if _, ok := s.entries[key]; !ok {
+			s.byNumber[entry.Number] = append(s.byNumber[entry.Number], key)
+		}
+		s.entries[key] = entry
+	}
+	for _, keys := range s.byNumber {
+		// Academic years sort as text, and migrated entries without a year sort last.
+		sort.Slice(keys, func(a, b int) bool {
+			return keys[a].year > keys[b].year
+		})
+	}
+
+	s.search = make([]courseSearchDoc, 0, len(s.byNumber))
+	for number := range s.byNumber {
+		entry, _ := s.preferredLocked(number)
+		s.search = append(s.search, newCourseSearchDoc(entry))
+	}
+}
+
+// reloadIfChangedLocked merges in the file if another process has written it since it was read.
+// s.mu must be held for writing.
+func (s *CourseStore) reloadIfChangedLocked() error {
+	info, err := os.Stat(s.path)
+	if err != nil || info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+	return s.mergeFileLocked()
+}
+
+// mergeFileLocked merges in the entries of the file. Entries in memory win over those in the
+// file if they were fetched later. s.mu must be held for writing.
+func (s *CourseStore) mergeFileLocked() error {
+	entries, modTime, err := readCourseStoreFile(s.path)
+	if err != nil {
+		return err
+	}
+	merged := s.entries
+	for _, entry := range entries {
+		key := courseKey{entry.Number, entry.AcademicYear}
+		if existing, ok := merged[key]; !ok || entry.UpdatedAt.After(existing.UpdatedAt) {
+			merged[key] = entry
+		}
+	}
+	s.setEntriesLocked(mapValues(merged))
+	s.modTime = modTime
+	return nil
+}
+
+// saveLocked writes the store, sorted by number and academic year. s.mu must be held.
+func (s *CourseStore) saveLocked() error {
+	entries := mapValues(s.entries)
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].Number != entries[b].Number {
+			return entries[a].Number < entries[b].Number
+		}
+		return entries[a].AcademicYear < entries[b].AcademicYear
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(s.path, buf.Bytes()); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// readCourseStoreFile reads a store file, one JSON object per line. A missing file
+// gives no entries and a zero modification time.
+func readCourseStoreFile(path string) ([]CourseIndexEntry, time.Time, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var entries []CourseIndexEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var entry CourseIndexEntry
+		if err := json.Unmarshal(text, &entry); err != nil {
+			return nil, time.Time{}, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, info.ModTime(), scanner.Err()
+}
+
+// parseLegacyCourses parses the entries of the flat course list, whose lines are
+// "number, title" or "number, title, academic year".
+func parseLegacyCourses(data []byte) []CourseIndexEntry {
+	var entries []CourseIndexEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		number, rest, _ := strings.Cut(line, ",")
+		entry := CourseIndexEntry{Number: strings.TrimSpace(number), Title: strings.TrimSpace(rest)}
+		// Titles may contain commas, so the academic year is only split off if the last part looks like one.
+		if idx := strings.LastIndex(rest, ","); idx >= 0 {
+			if year := strings.TrimSpace(rest[idx+1:]); academicYearPattern.MatchString(year) {
+				entry.Title, entry.AcademicYear = strings.TrimSpace(rest[:idx]), year
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func mapValues(entries map[courseKey]CourseIndexEntry) []CourseIndexEntry {
+	values := make([]CourseIndexEntry, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, entry)
+	}
+	return values
+}
+
+var (
+	courseStoreMu sync.Mutex
+	courseStore   *CourseStore
+)
+
+// SetCourseStore overrides the course store returned by GetCourseStore.
+func SetCourseStore(store *CourseStore) {
+	courseStoreMu.Lock()
+	defer courseStoreMu.Unlock()
+	courseStore = store
+}
+
+// GetCourseStore returns the course store, loading it from the configured file on first use.
+func GetCourseStore() (*CourseStore, error) {
+	courseStoreMu.Lock()
+	defer courseStoreMu.Unlock()
+
+	if courseStore == nil {
+		path := "data/course_index.jsonl"
+		if config.GlobalConfig != nil {
+			path = config.GlobalConfig.CourseIndexFile
+		}
+		store, err := OpenCourseStore(path)
+		if err != nil {
+			return nil, err
+		}
+		courseStore = store
+	}
+	return courseStore, nil
+}
+
+// StoreCourses records fetched courses in the course store, logging instead of failing,
+// since the store is only an index and the courses themselves were fetched fine.
+func StoreCourses(courses ...*Course) {
+	store, err := GetCourseStore()
+	if err != nil {
+		log.Println("Error loading the course store:", err)
+		return
+	}
+	if err := store.Put(courses...); err != nil {
+		log.Println("Error saving the course store:", err)
+	}
+}
@@ -0,0 +1,211 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOpenCourseStoreMergesLegacyCourses(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "course_index.jsonl")
+	legacy := filepath.Join(dir, "courses.txt")
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	openStore := func() *CourseStore {
+		t.Helper()
+		store, err := openCourseStore(path, legacy)
+		if err != nil {
+			t.Fatalf("openCourseStore: %v", err)
+		}
+		return store
+	}
+
+	// The store already exists, so the list is merged into it rather than replacing it.
+	writeFile(path, `{"number":"01001","title":"Mathematics 1a","academicYear":"2025-2026","updatedAt":"2025-09-01T00:00:00Z"}`+"\n")
+	writeFile(legacy, "01001, Old title\n02105, Algorithms and Data Structures 1\n")
+	store := openStore()
+	if entry, _ := store.Get("01001"); entry.Title != "Mathematics 1a" {
+		t.Errorf("01001 title = %q, want the stored title kept", entry.Title)
+	}
+	if _, ok := store.Get("02105"); !ok {
+		t.Error("02105 from the course list was not merged")
+	}
+
+	// An unchanged list is not merged again, so courses removed from the store stay removed.
+	writeFile(path, `{"number":"01001","title":"Mathematics 1a","academicYear":"2025-2026","updatedAt":"2025-09-01T00:00:00Z"}`+"\n")
+	if _, ok := openStore().Get("02105"); ok {
+		t.Error("02105 merged again from an unchanged course list")
+	}
+
+	// A changed list is merged again.
+	writeFile(legacy, "01001, Old title\n02105, Algorithms and Data Structures 1\n02110, Algorithms 2\n")
+	store = openStore()
+	for _, number := range []string{"02105", "02110"} {
+		if _, ok := store.Get(number); !ok {
+			t.Errorf("%s from the changed course list was not merged", number)
+		}
+	}
+}
+
+func TestCourseStoreConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "course_index.jsonl")
+
+	// Each store stands in for a separate process; only the file lock keeps them from
+	// overwriting each other's courses.
+	const writers = 8
+	stores := make([]*CourseStore, writers)
+	for idx := range stores {
+		store, err := openCourseStore(path, filepath.Join(t.TempDir(), "courses.txt"))
+		if err != nil {
+			t.Fatalf("openCourseStore: %v", err)
+		}
+		stores[idx] = store
+	}
+
+	var wg sync.WaitGroup
+	for idx, store := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 5; n++ {
+				entry := CourseIndexEntry{
+					Number:       fmt.Sprintf("%02d%03d", idx, n),
+					Title:        "Course",
+					AcademicYear: "2025-2026",
+					UpdatedAt:    time.Now(),
+				}
+				if err := store.PutEntries(entry); err != nil {
+					t.Errorf("PutEntries: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, err := openCourseStore(path, filepath.Join(t.TempDir(), "courses.txt"))
+	if err != nil {
+		t.Fatalf("openCourseStore: %v", err)
+	}
+	if got := store.Len(); got != writers*5 {
+		t.Errorf("store has %d courses, want %d", got, writers*5)
+	}
+}
+
+// testLookupStore is an in-memory store where 02105 is known for last, this and next academic
+// year, and 01017 only for last year.
+func testLookupStore() (store *CourseStore, previous, current, next string) {
+	now := time.Now()
+	previous, current, next = CurrentAcademicYear(now.AddDate(-1, 0, 0)), CurrentAcademicYear(now), CurrentAcademicYear(now.AddDate(1, 0, 0))
+	store = &CourseStore{}
+	store.setEntriesLocked([]CourseIndexEntry{
+		{Number: "02105", Title: "Algorithms 1 (old)", AcademicYear: previous, Department: "01 Department of Applied Mathematics and Computer Science"},
+		{Number: "02105", Title: "Algorithms and Data Structures 1", DanishTitle: "Algoritmer og datastrukturer 1", AcademicYear: current,
+			Department: "01 Department of Applied Mathematics and Computer Science", Teachers: []string{"Inge Li Gørtz", "Philip Bille"}},
+		{Number: "02105", Title: "Algorithms 1 (next)", AcademicYear: next, Department: "01 Department of Applied Mathematics and Computer Science"},
+		{Number: "01017", Title: "Discrete Mathematics", DanishTitle: "Diskret matematik", AcademicYear: previous,
+			Department: "01 Department of Applied Mathematics and Computer Science", Teachers: []string{"Jacob Fabricius"}},
+		{Number: "10020", Title: "Physics 1", AcademicYear: current, Department: "10 Department of Physics", Teachers: []string{"Philip Hofmann"}},
+		// Migrated from the flat course list, so without a year.
+		{Number: "42000", Title: "Economics"},
+	})
+	return store, previous, current, next
+}
+
+func TestCourseStoreGet(t *testing.T) {
+	store, previous, current, next := testLookupStore()
+	tests := []struct {
+		name      string
+		number    string
+		year      string
+		wantTitle string
+	}{
+		{"current year is preferred over newer and older ones", "02105", "", "Algorithms and Data Structures 1"},
+		{"newest year when the current one is unknown", "01017", "", "Discrete Mathematics"},
+		{"entry without a year", "42000", "", "Economics"},
+		{"unknown course", "99999", "", ""},
+		{"previous year", "02105", previous, "Algorithms 1 (old)"},
+		{"current year", "02105", current, "Algorithms and Data Structures 1"},
+		{"next year", "02105", next, "Algorithms 1 (next)"},
+		{"year the course is not known for", "01017", current, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entry CourseIndexEntry
+			var ok bool
+			if tt.year == "" {
+				entry, ok = store.Get(tt.number)
+			} else {
+				entry, ok = store.GetYear(tt.number, tt.year)
+			}
+			if ok != (tt.wantTitle != "") || entry.Title != tt.wantTitle {
+				t.Errorf("got %q (found %t), want %q", entry.Title, ok, tt.wantTitle)
+			}
+		})
+	}
+	if got := store.Len(); got != 4 {
+		t.Errorf("Len() = %d, want 4 distinct courses", got)
+	}
+}
+
+func TestCourseStoreGetPrefersNewestYear(t *testing.T) {
+	now := time.Now()
+	older, newer := CurrentAcademicYear(now.AddDate(-2, 0, 0)), CurrentAcademicYear(now.AddDate(-1, 0, 0))
+	store := &CourseStore{}
+	store.setEntriesLocked([]CourseIndexEntry{
+		{Number: "01017", Title: "Newer", AcademicYear: newer},
+		{Number: "01017", Title: "Migrated"},
+		{Number: "01017", Title: "Older", AcademicYear: older},
+	})
+	if entry, _ := store.Get("01017"); entry.Title != "Newer" {
+		t.Errorf("Get = %q, want the newest year", entry.Title)
+	}
+}
+
+func TestCourseStoreFind(t *testing.T) {
+	store, _, _, _ := testLookupStore()
+	tests := []struct {
+		name  string
+		find  func(string) []CourseIndexEntry
+		query string
+		want  []string
+	}{
+		{"title", store.FindByTitle, "algorithms", []string{"02105"}},
+		{"title ignores case", store.FindByTitle, "DISCRETE", []string{"01017"}},
+		{"Danish title", store.FindByTitle, "matematik", []string{"01017"}},
+		// Only the preferred version is searched, so the old title does not match.
+		{"title of another year", store.FindByTitle, "(old)", nil},
+		{"department number", store.FindByDepartment, "01", []string{"01017", "02105"}},
+		{"department name", store.FindByDepartment, "physics", []string{"10020"}},
+		{"teacher", store.FindByTeacher, "philip", []string{"02105", "10020"}},
+		{"teacher with Danish letters", store.FindByTeacher, "gørtz", []string{"02105"}},
+		{"unknown teacher", store.FindByTeacher, "nobody", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range tt.find(tt.query) {
+				got = append(got, entry.Number)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var all []string
+	for _, entry := range store.All() {
+		all = append(all, entry.Number)
+	}
+	if want := []string{"01017", "02105", "10020", "42000"}; !slices.Equal(all, want) {
+		t.Errorf("All() = %q, want %q", all, want)
+	}
+}
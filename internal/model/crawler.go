@@ -0,0 +1,213 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// catalogueSearchPath lists every course of the catalogue when searching without any criteria.
+const catalogueSearchPath = "/search?CourseCode=&SearchKeyword="
+
+// courseHrefPattern matches links to course pages, e.g. "/course/01001" or "/course/2025-2026/01001".
+var courseHrefPattern = regexp.MustCompile(`/course/(?:\d{4}-\d{4}/)?([0-9A-Za-z]{5})(?:[/?#]|$)`)
+
+// CrawlOptions controls how politely the crawler fetches the catalogue.
+type CrawlOptions struct {
+	// ListURL is the page linking to every course. Empty uses the catalogue's search page.
+	ListURL string
+	// Concurrency is the number of courses fetched at once.
+	Concurrency int
+	// Delay is the minimum time between the start of two course fetches.
+	Delay time.Duration
+}
+
+// CrawlOptionsFromConfig returns the crawl options of config.GlobalConfig.
+func CrawlOptionsFromConfig() CrawlOptions {
+	opts := CrawlOptions{Concurrency: 2, Delay: time.Second}
+	if cfg := config.GlobalConfig; cfg != nil {
+		opts = CrawlOptions{
+			ListURL:     cfg.CatalogueListURL,
+			Concurrency: cfg.CrawlConcurrency,
+			Delay:       cfg.CrawlDelay,
+		}
+	}
+	return opts
+}
+
+// CrawlResult summarizes a crawl.
+type CrawlResult struct {
+	// Listed is the number of courses found in the catalogue.
+	Listed int
+	// Fetched is the number of courses fetched, or taken from a fresh cache entry.
+	Fetched int
+	// Failed are the courses that could not be fetched. Their previous index entries are kept.
+	Failed   []string
+	Duration time.Duration
+}
+
+// ListCatalogueCourses returns the number of every course linked from the catalogue's course list, sorted.
+func ListCatalogueCourses(ctx context.Context, listURL string) ([]string, error) {
+	if listURL == "" {
+		listURL = CatalogueBaseURL() + catalogueSearchPath
+	}
+	// The list is plain server-rendered HTML, so it does not need the browser.
+	doc, err := utils.FetchStaticCoursePage(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var courses []string
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		m := courseHrefPattern.FindStringSubmatch(href)
+		if m == nil {
+			return
+		}
+		courseNumber := strings.ToUpper(m[1])
+		if !seen[courseNumber] && ValidateCourseNumber(courseNumber) == nil {
+			seen[courseNumber] = true
+			courses = append(courses, courseNumber)
+		}
+	})
+	if len(courses) == 0 {
+		return nil, fmt.Errorf("no courses found on %s", listURL)
+	}
+	sort.Strings(courses)
+	return courses, nil
+}
+
+// CrawlCatalogue fetches every course of the catalogue for the current academic year and records
+// them in the course store. Courses with a fresh cache entry are not fetched again. At most
+// opts.Concurrency courses are fetched at once, and fetches start at least opts.Delay apart;
+// courses taken from the cache are not delayed.
+// If ctx is cancelled, the courses fetched so far are still stored.
+func CrawlCatalogue(ctx context.Context, opts CrawlOptions) (CrawlResult, error) {
+	start := time.Now()
+	courseNumbers, err := ListCatalogueCourses(ctx, opts.ListURL)
+	if err != nil {
+		return CrawlResult{}, fmt.Errorf("listing catalogue courses: %w", err)
+	}
+	result := CrawlResult{Listed: len(courseNumbers)}
+	log.Printf("[Crawler] found %d courses in the catalogue", len(courseNumbers))
+
+	year := CurrentAcademicYear(time.Now())
+	courses := make([]*Course, 0, len(courseNumbers))
+	var mu sync.Mutex
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, opts.Concurrency); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for courseNumber := range jobs {
+				course, err := crawlCourse(courseNumber, year)
+				mu.Lock()
+				if err != nil {
+					log.Printf("[Crawler] %v", err)
+					result.Failed = append(result.Failed, courseNumber)
+				} else {
+					courses = append(courses, course)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// A ticker spaces out the fetches, so the catalogue is never hit in bursts. Courses with a
+	// fresh cache entry are taken as they are and do not wait for it.
+	cache := getCourseCache()
+	ticker := time.NewTicker(max(opts.Delay, time.Millisecond))
+	defer ticker.Stop()
+	sent := 0
+feed:
+	for _, courseNumber := range courseNumbers {
+		if ctx.Err() != nil {
+			break
+		}
+		if cached, fresh := cache.Get(courseNumber, year); cached != nil && fresh {
+			mu.Lock()
+			courses = append(courses, cached)
+			mu.Unlock()
+			continue
+		}
+		if sent > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		select {
+		case jobs <- courseNumber:
+			sent++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	result.Fetched = len(courses)
+	sort.Strings(result.Failed)
+	// Courses that failed keep their earlier entries, so a partial crawl never shrinks the store.
+	store, err := GetCourseStore()
+	if err != nil {
+		return result, fmt.Errorf("loading course store: %w", err)
+	}
+	if err := store.Put(courses...); err != nil {
+		return result, fmt.Errorf("writing course store: %w", err)
+	}
+	// The fetches only updated the reference index in memory; write it once for the whole crawl.
+	index, err := GetReferenceIndex()
+	if err != nil {
+		return result, fmt.Errorf("loading reference index: %w", err)
+	}
+	if err := index.Update(courses...); err != nil {
+		return result, fmt.Errorf("writing reference index: %w", err)
+	}
+
+	result.Duration = time.Since(start)
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("crawl stopped after %d of %d courses: %w", result.Fetched+len(result.Failed), result.Listed, ctx.Err())
+	}
+	return result, nil
+}
+
+// crawlCourse fetches the course and caches it.
+func crawlCourse(courseNumber, year string) (*Course, error) {
+	course, err := fetchCourseCoalesced(courseNumber, year)
+	if err != nil {
+		return nil, err
+	}
+	if err := getCourseCache().Put(course, year); err != nil {
+		log.Println("Failed to cache course:", err)
+	}
+	return course, nil
+}
+
+// ErrCrawlRunning is returned when a crawl is started while another one is still running.
+var ErrCrawlRunning = errors.New("a crawl is already running")
+
+var crawlRunning sync.Mutex
+
+// RunCrawl runs CrawlCatalogue with the configured options, unless a crawl is already running.
+func RunCrawl(ctx context.Context) (CrawlResult, error) {
+	if !crawlRunning.TryLock() {
+		return CrawlResult{}, ErrCrawlRunning
+	}
+	defer crawlRunning.Unlock()
+	return CrawlCatalogue(ctx, CrawlOptionsFromConfig())
+}
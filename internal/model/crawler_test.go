@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCrawlCatalogueOnlyDelaysFetches(t *testing.T) {
+	page, err := os.ReadFile("testdata/course_02105_en.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<a href="/course/01001">01001</a> <a href="/course/01002">01002</a> <a href="/course/02105">02105</a>`)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	store, err := openCourseStore(filepath.Join(dir, "course_index.jsonl"), filepath.Join(dir, "courses.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := NewReferenceIndex(filepath.Join(dir, "course_references.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	year := CurrentAcademicYear(time.Now())
+	cache := NewCourseCache(filepath.Join(dir, "cache"), time.Hour)
+	for _, number := range []string{"01001", "01002"} {
+		course := &Course{CourseNumber: number, Title: "Cached " + number, AcademicYear: year}
+		course.CourseAdditionalSection.FetchTime = time.Now()
+		if err := cache.Put(course, year); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source := &stubSource{name: "stub", html: string(page)}
+	SetCourseCache(cache)
+	SetCourseSource(source)
+	SetCourseStore(store)
+	SetReferenceIndex(index)
+	t.Cleanup(func() {
+		SetCourseCache(nil)
+		SetCourseSource(nil)
+		SetCourseStore(nil)
+		SetReferenceIndex(nil)
+	})
+
+	// With an hour between fetches, the crawl only finishes in time if the cached courses
+	// do not wait for the rate limit.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := CrawlCatalogue(ctx, CrawlOptions{ListURL: srv.URL, Concurrency: 1, Delay: time.Hour})
+	if err != nil {
+		t.Fatalf("CrawlCatalogue: %v", err)
+	}
+	if result.Listed != 3 || result.Fetched != 3 || len(result.Failed) != 0 {
+		t.Errorf("result = %+v, want 3 listed, 3 fetched and none failed", result)
+	}
+	if source.calls != 1 {
+		t.Errorf("source called %d times, want only 02105 fetched", source.calls)
+	}
+
+	for _, number := range []string{"01001", "01002", "02105"} {
+		if _, ok := store.Get(number); !ok {
+			t.Errorf("course %s missing from the store", number)
+		}
+	}
+	// The crawl writes the reference index once it is done.
+	if _, err := os.Stat(filepath.Join(dir, "course_references.json")); err != nil {
+		t.Errorf("reference index not written: %v", err)
+	}
+	if _, ok := index.Title("02105"); !ok {
+		t.Error("02105 missing from the reference index")
+	}
+}
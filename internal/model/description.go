@@ -0,0 +1,123 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Headings of the description blocks, English first and Danish second.
+var (
+	headingGeneralObjectives  = []string{"General course objectives", "Overall course objectives", "Overordnede kursusmål"}
+	headingLearningObjectives = []string{"Learning objectives", "Læringsmål"}
+	headingContent            = []string{"Content", "Indhold"}
+)
+
+// descriptionBlock is the content between one "div.bar" heading and the next.
+type descriptionBlock struct {
+	// lines are the paragraphs of text outside of lists.
+	lines []string
+	// items are the entries of any lists in the block.
+	items []string
+}
+
+// text returns the paragraphs of the block joined by newlines.
+func (b descriptionBlock) text() string {
+	return strings.Join(b.lines, "\n")
+}
+
+// parseDescription fills in the objectives and content sections from the blocks below the information table.
+func parseDescription(doc *goquery.Document, course *Course) {
+	blocks := parseDescriptionBlocks(doc)
+
+	if block, ok := findBlock(blocks, headingGeneralObjectives); ok {
+		course.CourseObjectivesSection.GeneralObjectives = block.text()
+	}
+	if block, ok := findBlock(blocks, headingLearningObjectives); ok {
+		course.CourseLearningObjectivesSection.Intro = block.text()
+		course.CourseLearningObjectivesSection.Objectives = block.items
+	}
+	if block, ok := findBlock(blocks, headingContent); ok {
+		// Content is free text, so any list items are kept as part of it.
+		lines := block.lines
+		for _, item := range block.items {
+			lines = append(lines, "• "+item)
+		}
+		course.CourseContentSection.Content = strings.Join(lines, "\n")
+	}
+}
+
+// findBlock returns the first block whose heading matches one of the given headings.
+func findBlock(blocks map[string]descriptionBlock, headings []string) (descriptionBlock, bool) {
+	for _, heading := range headings {
+		if block, ok := blocks[strings.ToLower(heading)]; ok {
+			return block, true
+		}
+	}
+	return descriptionBlock{}, false
+}
+
+// parseDescriptionBlocks collects the nodes following each "div.bar" heading,
+// up to the next heading, keyed by the lower-case heading text.
+func parseDescriptionBlocks(doc *goquery.Document) map[string]descriptionBlock {
+	blocks := make(map[string]descriptionBlock)
+
+	doc.Find("div.bar").Each(func(i int, bar *goquery.Selection) {
+		heading := strings.ToLower(normalizeText(strings.TrimSuffix(strings.TrimSpace(bar.Text()), ":")))
+		if heading == "" {
+			return
+		}
+		if _, exists := blocks[heading]; exists {
+			return
+		}
+
+		var block descriptionBlock
+		var paragraph strings.Builder
+		flush := func() {
+			if text := normalizeText(paragraph.String()); text != "" {
+				block.lines = append(block.lines, text)
+			}
+			paragraph.Reset()
+		}
+
+		started := false
+		bar.Parent().Contents().EachWithBreak(func(j int, node *goquery.Selection) bool {
+			if !started {
+				started = node.Nodes[0] == bar.Nodes[0]
+				return true
+			}
+			if node.Is("div.bar") {
+				return false
+			}
+
+			switch goquery.NodeName(node) {
+			case "#text":
+				paragraph.WriteString(node.Text())
+			case "br", "p":
+				flush()
+				paragraph.WriteString(node.Text())
+				flush()
+			case "ul", "ol":
+				flush()
+				node.Find("li").Each(func(k int, li *goquery.Selection) {
+					if item := normalizeText(li.Text()); item != "" {
+						block.items = append(block.items, item)
+					}
+				})
+			default:
+				paragraph.WriteString(" " + node.Text() + " ")
+			}
+			return true
+		})
+		flush()
+
+		blocks[heading] = block
+	})
+
+	return blocks
+}
+
+// normalizeText collapses all runs of whitespace into single spaces.
+func normalizeText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+)
+
+// maxChangeValueLength bounds each side of a change, so both fit in one embed field.
+const maxChangeValueLength = 480
+
+// FieldChange is one field that differs between two versions of a course.
+type FieldChange struct {
+	// Section is the name of the section the field belongs to, e.g. "Examination Details".
+	Section string
+	// Field is the readable field name, e.g. "Type of assessment".
+	Field string
+	// Path identifies the field in Course, e.g. "CourseExamSection.TypeOfAssessment".
+	Path     string
+	Old, New string
+	// OldLabel and NewLabel name the versions, e.g. "2024-2025" and "2025-2026".
+	OldLabel, NewLabel string
+	// Important is set for fields students are likely to be caught out by, such as the exam form.
+	Important bool
+}
+
+func (c FieldChange) GetSectionName() string {
+	name := fmt.Sprintf("%s: %s", c.Section, c.Field)
+	if strings.EqualFold(c.Section, c.Field) {
+		name = c.Section
+	}
+	if c.Important {
+		name = "⚠️ " + name
+	}
+	return name
+}
+
+func (c FieldChange) GetSectionValue() string {
+	return fmt.Sprintf("**%s:** %s\n**%s:** %s",
+		c.OldLabel, changeValue(c.Old), c.NewLabel, changeValue(c.New))
+}
+
+func (c FieldChange) GetSectionInline() bool {
+	return false
+}
+
+// SetInLine implements utils.Section.
+func (c FieldChange) SetInLine(IsInLine bool) {
+	panic("FieldChange does not support inline formatting")
+}
+
+// changeValue formats one side of a change for an embed field.
+func changeValue(value string) string {
+	if value == "" {
+		return "*(empty)*"
+	}
+	return utils.Truncate(value, maxChangeValueLength)
+}
+
+// importantFields are the paths of the fields that DiffCourses marks as important.
+var importantFields = map[string]bool{
+	"ECTS":                                          true,
+	"CourseScheduleSection.Schedule":                true,
+	"CourseExamSection.TypeOfAssessment":            true,
+	"CourseExamSection.Aid":                         true,
+	"CourseExamSection.DateOfExamination":           true,
+	"CourseAdditionalSection.AcademicPrerequisites": true,
+}
+
+// ignoredFields are the paths of fields that differ between fetches without the course changing.
+var ignoredFields = map[string]bool{
+	"CourseNumber":                      true,
+	"AcademicYear":                      true,
+	"IsInLine":                          true,
+	"CourseAdditionalSection.FetchTime": true,
+	"CourseAdditionalSection.Cached":    true,
+}
+
+// DiffCourses compares every field of two versions of a course and returns the ones that changed,
+// in the order they appear in Course, with the important ones first.
+// The labels name the versions, e.g. their academic years.
+func DiffCourses(old, new *Course, oldLabel, newLabel string) []FieldChange {
+	var changes []FieldChange
+	diffStruct(reflect.ValueOf(*old), reflect.ValueOf(*new), "", "Course", func(change FieldChange) {
+		change.OldLabel, change.NewLabel = oldLabel, newLabel
+		change.Important = importantFields[change.Path]
+		changes = append(changes, change)
+	})
+
+	var important, other []FieldChange
+	for _, change := range changes {
+		if change.Important {
+			important = append(important, change)
+		} else {
+			other = append(other, change)
+		}
+	}
+	return append(important, other...)
+}
+
+// diffStruct compares the fields of two structs of the same type. Nested section structs are
+// compared field by field; any other field is compared on its text form.
+func diffStruct(old, new reflect.Value, prefix, section string, report func(FieldChange)) {
+	for idx := 0; idx < old.NumField(); idx++ {
+		field := old.Type().Field(idx)
+		path := prefix + field.Name
+		if !field.IsExported() || ignoredFields[path] {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			diffStruct(old.Field(idx), new.Field(idx), path+".", sectionName(old.Field(idx), field.Name), report)
+			continue
+		}
+
+		oldText, newText := fieldText(old.Field(idx)), fieldText(new.Field(idx))
+		if oldText != newText {
+			report(FieldChange{Section: section, Field: readableName(field.Name), Path: path, Old: oldText, New: newText})
+		}
+	}
+}
+
+// sectionName returns the name a section shows in the course embed, or a name derived from its field.
+func sectionName(section reflect.Value, fieldName string) string {
+	if named, ok := section.Interface().(interface{ GetSectionName() string }); ok {
+		return named.GetSectionName()
+	}
+	return readableName(strings.TrimSuffix(fieldName, "Section"))
+}
+
+// fieldText returns the text form of a field value used for comparing and showing it.
+func fieldText(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String())
+	case reflect.Slice:
+		parts := make([]string, 0, value.Len())
+		for idx := 0; idx < value.Len(); idx++ {
+			if text := fieldText(value.Index(idx)); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	case reflect.Struct:
+		var parts []string
+		for idx := 0; idx < value.NumField(); idx++ {
+			if !value.Type().Field(idx).IsExported() {
+				continue
+			}
+			if text := fieldText(value.Field(idx)); text != "" {
+				parts = append(parts, strings.ReplaceAll(text, "\n", ", "))
+			}
+		}
+		return strings.Join(parts, ": ")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// readableName turns a Go name into words, e.g. "TypeOfAssessment" into "Type of assessment".
+// Runs of capitals such as "ECTS" are kept as they are.
+func readableName(name string) string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for idx := 1; idx <= len(runes); idx++ {
+		boundary := idx == len(runes) ||
+			unicode.IsUpper(runes[idx]) && (unicode.IsLower(runes[idx-1]) ||
+				idx+1 < len(runes) && unicode.IsLower(runes[idx+1]))
+		if boundary {
+			words = append(words, string(runes[start:idx]))
+			start = idx
+		}
+	}
+
+	for idx, word := range words {
+		if idx > 0 && strings.ToUpper(word) != word {
+			words[idx] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
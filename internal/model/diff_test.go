@@ -0,0 +1,153 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// diffBaseCourse is the old version each diff case changes.
+func diffBaseCourse() *Course {
+	course := &Course{
+		CourseNumber: "02105",
+		AcademicYear: "2024-2025",
+		Title:        "Algorithms and Data Structures 1",
+		ECTS:         "5",
+	}
+	course.CourseScheduleSection.Schedule = "F3A"
+	course.CourseExamSection.TypeOfAssessment = "Written examination"
+	course.CourseExamSection.Aid = "All aids"
+	course.CourseExamSection.DateOfExamination = "F3A"
+	course.CourseAdditionalSection.AcademicPrerequisites = "[01017](/course/01017)"
+	course.CourseLearningObjectivesSection.Objectives = []string{"Analyse algorithms", "Design data structures"}
+	course.CourseAdditionalSection.FetchTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return course
+}
+
+func TestDiffCourses(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Course)
+		want   []FieldChange
+	}{
+		{
+			name:   "ECTS",
+			change: func(c *Course) { c.ECTS = "7.5" },
+			want:   []FieldChange{{Section: "Course", Field: "ECTS", Path: "ECTS", Old: "5", New: "7.5", Important: true}},
+		},
+		{
+			name:   "exam form",
+			change: func(c *Course) { c.CourseExamSection.TypeOfAssessment = "Oral examination" },
+			want: []FieldChange{{Section: "Examination Details", Field: "Type of assessment", Path: "CourseExamSection.TypeOfAssessment",
+				Old: "Written examination", New: "Oral examination", Important: true}},
+		},
+		{
+			name:   "aids",
+			change: func(c *Course) { c.CourseExamSection.Aid = "No aids" },
+			want: []FieldChange{{Section: "Examination Details", Field: "Aid", Path: "CourseExamSection.Aid",
+				Old: "All aids", New: "No aids", Important: true}},
+		},
+		{
+			name:   "schedule",
+			change: func(c *Course) { c.CourseScheduleSection.Schedule = "F5B" },
+			want: []FieldChange{{Section: "Schedule & Location", Field: "Schedule", Path: "CourseScheduleSection.Schedule",
+				Old: "F3A", New: "F5B", Important: true}},
+		},
+		{
+			name:   "exam date",
+			change: func(c *Course) { c.CourseExamSection.DateOfExamination = "F5B" },
+			want: []FieldChange{{Section: "Examination Details", Field: "Date of examination", Path: "CourseExamSection.DateOfExamination",
+				Old: "F3A", New: "F5B", Important: true}},
+		},
+		{
+			name:   "prerequisites",
+			change: func(c *Course) { c.CourseAdditionalSection.AcademicPrerequisites = "" },
+			want: []FieldChange{{Section: "Additional Information", Field: "Academic prerequisites", Path: "CourseAdditionalSection.AcademicPrerequisites",
+				Old: "[01017](/course/01017)", Important: true}},
+		},
+		{
+			name:   "field that is not highlighted",
+			change: func(c *Course) { c.Title = "Algorithms 1" },
+			want:   []FieldChange{{Section: "Course", Field: "Title", Path: "Title", Old: "Algorithms and Data Structures 1", New: "Algorithms 1"}},
+		},
+		{
+			name:   "list field",
+			change: func(c *Course) { c.CourseLearningObjectivesSection.Objectives = []string{"Analyse algorithms"} },
+			want: []FieldChange{{Section: "Learning Objectives", Field: "Objectives", Path: "CourseLearningObjectivesSection.Objectives",
+				Old: "Analyse algorithms\nDesign data structures", New: "Analyse algorithms"}},
+		},
+		{
+			name:   "surrounding whitespace is not a change",
+			change: func(c *Course) { c.ECTS = " 5\n" },
+		},
+		{
+			name: "fetch details and the academic year are not changes",
+			change: func(c *Course) {
+				c.AcademicYear = "2025-2026"
+				c.CourseAdditionalSection.FetchTime = time.Now()
+				c.CourseAdditionalSection.Cached = true
+			},
+		},
+		{
+			name: "important changes come first",
+			change: func(c *Course) {
+				c.Title = "Algorithms 1"
+				c.CourseExamSection.Aid = "No aids"
+			},
+			want: []FieldChange{
+				{Section: "Examination Details", Field: "Aid", Path: "CourseExamSection.Aid", Old: "All aids", New: "No aids", Important: true},
+				{Section: "Course", Field: "Title", Path: "Title", Old: "Algorithms and Data Structures 1", New: "Algorithms 1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new := diffBaseCourse(), diffBaseCourse()
+			tt.change(new)
+			got := DiffCourses(old, new, "2024-2025", "2025-2026")
+			if len(got) != len(tt.want) {
+				t.Fatalf("DiffCourses = %+v, want %d change(s)", got, len(tt.want))
+			}
+			for idx, want := range tt.want {
+				want.OldLabel, want.NewLabel = "2024-2025", "2025-2026"
+				if got[idx] != want {
+					t.Errorf("change %d = %+v, want %+v", idx, got[idx], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldChangeFormatting(t *testing.T) {
+	change := FieldChange{Section: "Examination Details", Field: "Aid", New: "No aids", OldLabel: "Before", NewLabel: "Now", Important: true}
+	if got := change.GetSectionName(); got != "⚠️ Examination Details: Aid" {
+		t.Errorf("GetSectionName = %q", got)
+	}
+	if got := change.GetSectionValue(); got != "**Before:** *(empty)*\n**Now:** No aids" {
+		t.Errorf("GetSectionValue = %q", got)
+	}
+
+	// A field named like its section is shown once, and long values are truncated.
+	change = FieldChange{Section: "Content", Field: "Content", Old: strings.Repeat("x", 1000), New: "y", OldLabel: "Before", NewLabel: "Now"}
+	if got := change.GetSectionName(); got != "Content" {
+		t.Errorf("GetSectionName = %q, want Content", got)
+	}
+	if got := len(change.GetSectionValue()); got > 2*maxChangeValueLength {
+		t.Errorf("GetSectionValue is %d characters, want the values truncated", got)
+	}
+}
+
+func TestReadableName(t *testing.T) {
+	tests := map[string]string{
+		"TypeOfAssessment":          "Type of assessment",
+		"ECTS":                      "ECTS",
+		"NotApplicableTogetherWith": "Not applicable together with",
+		"HTMLPage":                  "HTML page",
+		"Aid":                       "Aid",
+	}
+	for name, want := range tests {
+		if got := readableName(name); got != want {
+			t.Errorf("readableName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+)
+
+// Kinds of fetch errors. Use errors.Is to check which kind a FetchError is.
+var (
+	ErrInvalidCourseNumber = errors.New("invalid course number")
+	ErrInvalidAcademicYear = errors.New("invalid academic year")
+	ErrCourseNotFound      = errors.New("course not found")
+	ErrFetchTimeout        = errors.New("timed out fetching course")
+	ErrSiteUnavailable     = errors.New("course catalogue unavailable")
+	ErrParseFailure        = errors.New("failed to parse course page")
+)
+
+// FetchError describes why a course could not be fetched.
+type FetchError struct {
+	CourseNumber string
+	// AcademicYear is the catalogue version that was requested, if known.
+	AcademicYear string
+	// Kind is one of the Err* values above.
+	Kind error
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	course := e.CourseNumber
+	if e.AcademicYear != "" {
+		course += " (" + e.AcademicYear + ")"
+	}
+	if e.Err == nil {
+		return fmt.Sprintf("course %s: %v", course, e.Kind)
+	}
+	return fmt.Sprintf("course %s: %v: %v", course, e.Kind, e.Err)
+}
+
+// Unwrap makes both the kind and the underlying error visible to errors.Is and errors.As.
+func (e *FetchError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// courseNumberPattern matches DTU course numbers, which are five characters such as "02105".
+var courseNumberPattern = regexp.MustCompile(`^[0-9A-Z]{5}$`)
+
+// ValidateCourseNumber returns a FetchError of kind ErrInvalidCourseNumber for malformed input.
+func ValidateCourseNumber(courseNumber string) error {
+	if !courseNumberPattern.MatchString(strings.ToUpper(courseNumber)) {
+		return &FetchError{CourseNumber: courseNumber, Kind: ErrInvalidCourseNumber}
+	}
+	return nil
+}
+
+// academicYearPattern matches academic years such as "2025-2026".
+var academicYearPattern = regexp.MustCompile(`^(\d{4})-(\d{4})$`)
+
+// ValidateAcademicYear returns a FetchError of kind ErrInvalidAcademicYear unless the year
+// is two consecutive years, e.g. "2025-2026".
+func ValidateAcademicYear(courseNumber, year string) error {
+	if m := academicYearPattern.FindStringSubmatch(year); m != nil {
+		start, _ := strconv.Atoi(m[1])
+		end, _ := strconv.Atoi(m[2])
+		if end == start+1 {
+			return nil
+		}
+	}
+	return &FetchError{CourseNumber: courseNumber, AcademicYear: year, Kind: ErrInvalidAcademicYear}
+}
+
+// classifySourceError turns an error from a CourseSource into a FetchError.
+func classifySourceError(courseNumber, year string, err error) error {
+	var statusErr *utils.HTTPStatusError
+	var netErr net.Error
+
+	fetchErr := &FetchError{CourseNumber: courseNumber, AcademicYear: year, Err: err}
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		fetchErr.Kind = ErrCourseNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		fetchErr.Kind = ErrFetchTimeout
+	default:
+		fetchErr.Kind = ErrSiteUnavailable
+	}
+	return fetchErr
+}
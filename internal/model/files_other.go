@@ -0,0 +1,15 @@
+//go:build !unix
+
+package model
+
+import "os"
+
+// Files are not locked on these platforms, so only one process may write the course store at a time.
+
+func lockFileHandle(f *os.File) error {
+	return nil
+}
+
+func unlockFileHandle(f *os.File) error {
+	return nil
+}
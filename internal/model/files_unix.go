@@ -0,0 +1,16 @@
+//go:build unix
+
+package model
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFileHandle(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFileHandle(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
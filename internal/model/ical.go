@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// calendarTimezone is the timezone DTU schedules are given in.
+const calendarTimezone = "Europe/Copenhagen"
+
+// calendarVTimezone describes calendarTimezone, since not every client knows IANA names.
+var calendarVTimezone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:" + calendarTimezone,
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:+0100",
+	"TZOFFSETTO:+0200",
+	"TZNAME:CEST",
+	"DTSTART:19700329T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:+0200",
+	"TZOFFSETTO:+0100",
+	"TZNAME:CET",
+	"DTSTART:19701025T030000",
+	"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+// calendarWriter builds an iCalendar (RFC 5545) document.
+type calendarWriter struct {
+	sb     strings.Builder
+	stamp  string
+	events int
+}
+
+// line writes a content line, folded at 75 octets as the format requires.
+func (w *calendarWriter) line(s string) {
+	for len(s) > 75 {
+		cut := 75
+		// Do not split a multi-byte character across lines.
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.sb.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+	}
+	w.sb.WriteString(s + "\r\n")
+}
+
+// event writes a VEVENT with the given properties in addition to UID, DTSTAMP and SUMMARY.
+// Empty properties are skipped.
+func (w *calendarWriter) event(uid, summary, description string, props ...string) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + uid)
+	w.line("DTSTAMP:" + w.stamp)
+	w.line("SUMMARY:" + escapeCalendarText(summary))
+	if description != "" {
+		w.line("DESCRIPTION:" + escapeCalendarText(description))
+	}
+	for _, prop := range props {
+		if prop == "" {
+			continue
+		}
+		w.line(prop)
+	}
+	w.line("END:VEVENT")
+	w.events++
+}
+
+// BuildCalendar exports the courses' schedule and exams in the given semester as an iCalendar file,
+// using the dates of the academic calendar. Every 13-week block becomes a weekly event from the
+// semester start to the end of its last teaching week, every 3-week period an event on each weekday
+// of the period, and every exam an all-day event. Exams placed on a block code whose exam day is
+// not in the calendar span the exam period instead. Occurrences during holidays are excluded.
+// 3-week periods that are missing from the academic calendar are left out.
+func BuildCalendar(courses []*Course, semester SemesterKey, now time.Time) ([]byte, error) {
+	academic := GetAcademicCalendar()
+	academicYear := semester.AcademicYear()
+	dates, ok := academic.Semester(semester)
+	if !ok {
+		return nil, fmt.Errorf("%w for %s (%s)", ErrCalendarNotConfigured, academicYear, semester)
+	}
+
+	until := dates.End
+	if last, ok := academic.TeachingWeek(semester, dates.Weeks()); ok {
+		until = last.End
+	}
+
+	w := &calendarWriter{stamp: now.UTC().Format("20060102T150405Z")}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Discord-Bot-DTU//Course schedule//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escapeCalendarText("DTU "+semester.String()))
+	w.line("X-WR-TIMEZONE:" + calendarTimezone)
+	for _, line := range calendarVTimezone {
+		w.line(line)
+	}
+
+	for _, course := range courses {
+		summary := fmt.Sprintf("%s %s", course.CourseNumber, course.Title)
+		var location string
+		if course.CourseScheduleSection.Location != "" {
+			location = "LOCATION:" + escapeCalendarText(course.CourseScheduleSection.Location)
+		}
+		threeWeekSeen := make(map[string]bool)
+
+		for _, slot := range course.CourseScheduleSection.ParsedSchedule().Slots {
+			if slot.Semester != "" && slot.Semester != semester.Semester {
+				continue
+			}
+
+			if slot.Period == Period3Week {
+				if threeWeekSeen[slot.Block] {
+					continue
+				}
+				threeWeekSeen[slot.Block] = true
+				period, ok := academic.ThreeWeekPeriod(slot.Block, semester)
+				if !ok {
+					continue
+				}
+				first := period.Start
+				for first.Weekday() == time.Saturday || first.Weekday() == time.Sunday {
+					first = first.AddDate(0, 0, 1)
+				}
+				w.event(calendarUID(course, semester, slot.Block), summary, slot.Block+" 3-week period",
+					"DTSTART;TZID="+calendarTimezone+":"+calendarLocalTime(first, slot.Start),
+					"DTEND;TZID="+calendarTimezone+":"+calendarLocalTime(first, slot.End),
+					"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;UNTIL="+calendarUntil(period.End),
+					holidayExceptions(academic.Holidays(academicYear, period), first, 1, period.End, slot.Start),
+					location)
+				continue
+			}
+
+			first := firstWeekday(dates.Start, slot.Weekday)
+			if first.After(until) {
+				continue
+			}
+			w.event(calendarUID(course, semester, slot.Block), summary, slot.Block,
+				"DTSTART;TZID="+calendarTimezone+":"+calendarLocalTime(first, slot.Start),
+				"DTEND;TZID="+calendarTimezone+":"+calendarLocalTime(first, slot.End),
+				"RRULE:FREQ=WEEKLY;UNTIL="+calendarUntil(until),
+				holidayExceptions(academic.Holidays(academicYear, dates.DateRange), first, 7, until, slot.Start),
+				location)
+		}
+
+		exam := ParseExamPlacement(course.CourseExamSection.DateOfExamination)
+		var examDays []DateRange
+		for _, date := range exam.Dates {
+			examDays = append(examDays, DateRange{Start: date, End: date})
+		}
+		// Exams placed on a block code, e.g. "F3A", take place on that block's exam day, or
+		// somewhere in the exam period while the day is not in the calendar.
+		for _, code := range exam.Codes {
+			if codeSemester(code) != semester.Semester {
+				continue
+			}
+			if days, ok := academic.Exam(code, academicYear); ok && !slices.Contains(examDays, days) {
+				examDays = append(examDays, days)
+			}
+		}
+		description := strings.TrimSpace(course.CourseExamSection.TypeOfAssessment + "\n" + course.CourseExamSection.DateOfExamination)
+		for _, days := range examDays {
+			examSummary := "Exam: " + summary
+			if !days.Start.Equal(days.End) {
+				examSummary = "Exam period: " + summary
+			}
+			w.event(calendarUID(course, semester, "exam-"+days.Start.Format("20060102")), examSummary, description,
+				"DTSTART;VALUE=DATE:"+days.Start.Format("20060102"),
+				"DTEND;VALUE=DATE:"+days.End.AddDate(0, 0, 1).Format("20060102"),
+				"TRANSP:TRANSPARENT")
+		}
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.sb.String()), nil
+}
+
+// holidayExceptions returns an EXDATE property for the occurrences of a recurring event that
+// fall on a holiday, or an empty string if there are none. The event occurs every stepDays days
+// from first until the end date, at the given offset from midnight; weekends are never occurrences.
+func holidayExceptions(holidays []Holiday, first time.Time, stepDays int, until time.Time, offset time.Duration) string {
+	var excluded []string
+	for day := first; !day.After(until); day = day.AddDate(0, 0, stepDays) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		for _, holiday := range holidays {
+			if holiday.Contains(day) {
+				excluded = append(excluded, calendarLocalTime(day, offset))
+				break
+			}
+		}
+	}
+	if len(excluded) == 0 {
+		return ""
+	}
+	return "EXDATE;TZID=" + calendarTimezone + ":" + strings.Join(excluded, ",")
+}
+
+// firstWeekday returns the first date on or after start that falls on the weekday.
+func firstWeekday(start time.Time, weekday time.Weekday) time.Time {
+	return start.AddDate(0, 0, (int(weekday)-int(start.Weekday())+7)%7)
+}
+
+// calendarLocalTime formats a date plus an offset from midnight as a local date-time.
+func calendarLocalTime(date time.Time, offset time.Duration) string {
+	return date.Add(offset).Format("20060102T150405")
+}
+
+// calendarUntil formats the end of the given day as an UNTIL value. UNTIL must be in UTC when
+// DTSTART has a timezone, so the end of the local day is used, which covers every event that day.
+func calendarUntil(date time.Time) string {
+	return date.Add(24*time.Hour - time.Second).Format("20060102T150405Z")
+}
+
+func calendarUID(course *Course, semester SemesterKey, part string) string {
+	id := fmt.Sprintf("%s-%s-%d-%s@discord-bot-dtu", course.CourseNumber, semester.Semester, semester.Year, part)
+	return strings.ToLower(strings.ReplaceAll(id, " ", "-"))
+}
+
+// calendarTextEscaper escapes the characters that are special in TEXT values.
+var calendarTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeCalendarText(s string) string {
+	return calendarTextEscaper.Replace(s)
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildFixtureCalendar exports the recorded 02105 page, with the shipped academic calendar,
+// and returns the content lines of the file unfolded.
+func buildFixtureCalendar(t *testing.T, semester SemesterKey, location string) (raw string, lines []string) {
+	t.Helper()
+	calendar, err := LoadAcademicCalendar("../../data/academic_calendar.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetAcademicCalendar(calendar)
+	t.Cleanup(func() { SetAcademicCalendar(nil) })
+
+	course := parseFixture(t, "course_02105_en.html")
+	if location != "" {
+		course.CourseScheduleSection.Location = location
+	}
+	data, err := BuildCalendar([]*Course{course}, semester, time.Date(2025, time.August, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw = string(data)
+	if !strings.HasSuffix(raw, "\r\n") {
+		t.Errorf("the calendar does not end with CRLF")
+	}
+	unfolded := strings.ReplaceAll(raw, "\r\n ", "")
+	return raw, strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n")
+}
+
+// eventProperties returns the properties of each VEVENT, keyed by name with parameters.
+func eventProperties(lines []string) []map[string]string {
+	var events []map[string]string
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			events = append(events, make(map[string]string))
+		case line == "END:VEVENT" || len(events) == 0:
+		default:
+			name, value, _ := strings.Cut(line, ":")
+			events[len(events)-1][name] = value
+		}
+	}
+	return events
+}
+
+func TestBuildCalendarWeeklyBlock(t *testing.T) {
+	raw, lines := buildFixtureCalendar(t, SemesterKey{Semester: SemesterAutumn, Year: 2025}, "Building 321, room 033; Lyngby")
+
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Errorf("the calendar starts with %q and ends with %q", lines[0], lines[len(lines)-1])
+	}
+	for _, line := range strings.Split(raw, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets is not folded: %q", len(line), line)
+		}
+	}
+
+	// The course is taught in E3A, Tuesday 8-12, and its exam is in the spring.
+	events := eventProperties(lines)
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want the E3A block only: %v", len(events), events)
+	}
+	event := events[0]
+	for _, tc := range []struct{ name, want string }{
+		{"UID", "02105-autumn-2025-e3a@discord-bot-dtu"},
+		{"SUMMARY", "02105 Algorithms and Data Structures 1"},
+		{"DTSTART;TZID=Europe/Copenhagen", "20250902T080000"},
+		{"DTEND;TZID=Europe/Copenhagen", "20250902T120000"},
+		// The semester ends on Friday 5 December, after 13 teaching weeks.
+		{"RRULE", "FREQ=WEEKLY;UNTIL=20251205T235959Z"},
+		// Tuesday of the autumn break is skipped.
+		{"EXDATE;TZID=Europe/Copenhagen", "20251014T080000"},
+		{"LOCATION", `Building 321\, room 033\; Lyngby`},
+	} {
+		if got := event[tc.name]; got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBuildCalendarExamPeriod(t *testing.T) {
+	raw, lines := buildFixtureCalendar(t, SemesterKey{Semester: SemesterSpring, Year: 2026}, "")
+
+	// F3A has no exam day in the shipped calendar, so the exam spans the summer exam period.
+	events := eventProperties(lines)
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want the exam only: %v", len(events), events)
+	}
+	event := events[0]
+	for _, tc := range []struct{ name, want string }{
+		{"UID", "02105-spring-2026-exam-20260511@discord-bot-dtu"},
+		{"SUMMARY", "Exam period: 02105 Algorithms and Data Structures 1"},
+		{"DTSTART;VALUE=DATE", "20260511"},
+		// DTEND of an all-day event is exclusive, so the day after the period ends.
+		{"DTEND;VALUE=DATE", "20260530"},
+		{"DESCRIPTION", `Written examination\n[F3A](http://www.dtu.dk/Uddannelse/Eksamen)`},
+	} {
+		if got := event[tc.name]; got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// The description is longer than a line, so it is folded.
+	if !strings.Contains(raw, "\r\n )") {
+		t.Errorf("the description is not folded:\n%s", raw)
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrNoCourseInformation is returned when a page does not contain any course information,
+// e.g. because the course number does not exist.
+var ErrNoCourseInformation = errors.New("no course information found on page")
+
+// Labels of the course information table rows, English first and Danish second.
+// They are matched exactly against the normalized label text (see utils.NormalizeLabel).
+var (
+	labelDanishTitle               = []string{"Danish title"}
+	labelEnglishTitle              = []string{"English title", "Engelsk titel"}
+	labelLanguage                  = []string{"Language of instruction", "Undervisningssprog"}
+	labelECTS                      = []string{"Point( ECTS )", "Point (ECTS)"}
+	labelCourseType                = []string{"Course type", "Kursustype"}
+	labelSchedule                  = []string{"Schedule", "Skemaplacering"}
+	labelLocation                  = []string{"Location", "Undervisningens placering"}
+	labelScopeAndForm              = []string{"Scope and form", "Undervisningsform"}
+	labelDuration                  = []string{"Duration of course", "Kursets varighed"}
+	labelDateOfExamination         = []string{"Date of examination", "Eksamensplacering"}
+	labelTypeOfAssessment          = []string{"Type of assessment", "Evalueringsform"}
+	labelExamDuration              = []string{"Exam duration", "Eksamens varighed"}
+	labelAid                       = []string{"Aid", "Hjælpemidler"}
+	labelEvaluation                = []string{"Evaluation", "Bedømmelsesform"}
+	labelNotApplicableTogetherWith = []string{"Not applicable together with", "Ikke godkendt sammen med"}
+	labelAcademicPrerequisites     = []string{"Academic prerequisites", "Faglige forudsætninger"}
+	labelResponsible               = []string{"Responsible", "Kursusansvarlig"}
+	labelCoResponsible             = []string{"Course co-responsible", "Kursusmedansvarlige", "Kursusmedansvarlig"}
+	labelDepartment                = []string{"Department", "Institut"}
+	labelDepartmentInvolved        = []string{"Department involved", "Deltagende institut"}
+	labelHomePage                  = []string{"Home page", "Hjemmeside"}
+	labelRegistrationSignUp        = []string{"Registration sign-up", "Tilmelding"}
+)
+
+// ParseCourse parses a course page, e.g. a saved HTML file, into a Course struct.
+func ParseCourse(r io.Reader, courseNumber string) (*Course, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCourseDocument(doc, courseNumber)
+}
+
+// ParseCourseDocument extracts the course details from an already loaded course page.
+func ParseCourseDocument(doc *goquery.Document, courseNumber string) (*Course, error) {
+	// Check if the main info is actually present
+	if doc.Find("div.box.information").Length() == 0 {
+		return nil, ErrNoCourseInformation
+	}
+
+	// Create a new Course object
+	course := &Course{
+		CourseNumber: courseNumber,
+		CourseAdditionalSection: CourseAdditionalSection{
+			FetchTime: time.Now(),
+		},
+	}
+
+	// Parse the course details
+	rawTitle := utils.ExtractText(doc, "div.col-xs-8 h2", "")
+	// We remove the leading course number from the title e.g., "10060 Physics (Polytechnical Foundation) -> Physics (Polytechnical Foundation)"
+	if parts := strings.SplitN(strings.TrimSpace(rawTitle), " ", 2); len(parts) == 2 {
+		course.Title = strings.TrimSpace(parts[1])
+	}
+	table := utils.ParseInfoTable(doc)
+	course.DanishTitle = table.Value(labelDanishTitle...)
+	// The Danish page has the Danish title as its heading and lists the English one in the table.
+	if englishTitle := table.Value(labelEnglishTitle...); englishTitle != "" {
+		course.Title, course.DanishTitle = englishTitle, course.Title
+	}
+	course.LanguageOfInstruction = table.Value(labelLanguage...)
+	course.ECTS = table.Value(labelECTS...)
+	if field, ok := table.Get(labelCourseType...); ok {
+		course.CourseTypeSection.CourseType = utils.ExtractCourseTypeAdvanced(field.Cell)
+	}
+	course.CourseScheduleSection.Schedule = table.Value(labelSchedule...)
+	course.CourseScheduleSection.Location = table.Value(labelLocation...)
+	course.CourseScheduleSection.ScopeAndForm = table.Value(labelScopeAndForm...)
+	course.CourseScheduleSection.DurationOfCourse = table.Value(labelDuration...)
+	course.CourseExamSection.DateOfExamination = table.Value(labelDateOfExamination...)
+	course.CourseExamSection.TypeOfAssessment = table.Value(labelTypeOfAssessment...)
+	course.CourseExamSection.ExamDuration = table.Value(labelExamDuration...)
+	course.CourseExamSection.Aid = table.Value(labelAid...)
+	course.CourseExamSection.Evaluation = table.Value(labelEvaluation...)
+	course.CourseAdditionalSection.NotApplicableTogetherWith = table.Value(labelNotApplicableTogetherWith...)
+	course.CourseAdditionalSection.AcademicPrerequisites = table.Value(labelAcademicPrerequisites...)
+	course.CourseResponsibleSection.Responsible = table.Value(labelResponsible...)
+	course.CourseResponsibleSection.CourseCoResponsible = table.Value(labelCoResponsible...)
+	course.CourseAdditionalSection.Department = table.Value(labelDepartment...)
+	course.CourseAdditionalSection.DepartmentInvolved = table.Value(labelDepartmentInvolved...)
+	course.CourseAdditionalSection.HomePage = table.Value(labelHomePage...)
+	course.CourseAdditionalSection.RegistrationSignUp = table.Value(labelRegistrationSignUp...)
+
+	parseDescription(doc, course)
+
+	// Validate that we actually found some data
+	if course.Title == "" && course.DanishTitle == "" && course.ECTS == "" {
+		return nil, ErrNoCourseInformation
+	}
+
+	return course, nil
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// parseFixture parses a recorded course page from testdata.
+func parseFixture(t *testing.T, name string) *Course {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	course, err := ParseCourse(f, "02105")
+	if err != nil {
+		t.Fatalf("ParseCourse(%s): %v", name, err)
+	}
+	return course
+}
+
+func TestParseCourseEnglish(t *testing.T) {
+	course := parseFixture(t, "course_02105_en.html")
+
+	for _, tc := range []struct {
+		field, got, want string
+	}{
+		{"CourseNumber", course.CourseNumber, "02105"},
+		{"Title", course.Title, "Algorithms and Data Structures 1"},
+		{"DanishTitle", course.DanishTitle, "Algoritmer og datastrukturer 1"},
+		{"LanguageOfInstruction", course.LanguageOfInstruction, "English"},
+		{"ECTS", course.ECTS, "7.5"},
+		{"Schedule", course.CourseScheduleSection.Schedule, "Spring E3A (Tue 8-12)"},
+		{"Location", course.CourseScheduleSection.Location, "Campus Lyngby"},
+		{"ScopeAndForm", course.CourseScheduleSection.ScopeAndForm, "Lectures and exercises"},
+		{"DurationOfCourse", course.CourseScheduleSection.DurationOfCourse, "13 weeks"},
+		{"DateOfExamination", course.CourseExamSection.DateOfExamination, "[F3A](http://www.dtu.dk/Uddannelse/Eksamen)"},
+		{"TypeOfAssessment", course.CourseExamSection.TypeOfAssessment, "Written examination"},
+		{"ExamDuration", course.CourseExamSection.ExamDuration, "Written exam: 4 hours"},
+		{"Aid", course.CourseExamSection.Aid, "All Aid - no access to the internet"},
+		{"Evaluation", course.CourseExamSection.Evaluation, "7 step scale , external examiner"},
+		{"NotApplicableTogetherWith", course.CourseAdditionalSection.NotApplicableTogetherWith, "[02326](/course/02326)"},
+		{"AcademicPrerequisites", course.CourseAdditionalSection.AcademicPrerequisites,
+			"[01017](/course/2025-2026/01017) / [02100](/course/02100) or equivalent programming experience"},
+		{"Responsible", course.CourseResponsibleSection.Responsible, "Inge Li Gørtz , Lyngby Campus, Building 321, (mailto:inge@dtu.dk)"},
+		{"CourseCoResponsible", course.CourseResponsibleSection.CourseCoResponsible, "Philip Bille , Lyngby Campus, Building 321"},
+		{"Department", course.CourseAdditionalSection.Department,
+			"[01 Department of Applied Mathematics and Computer Science](http://www.compute.dtu.dk)"},
+		// "Department" must not pick up the "Department involved" row, which this page does not have.
+		{"DepartmentInvolved", course.CourseAdditionalSection.DepartmentInvolved, ""},
+		{"HomePage", course.CourseAdditionalSection.HomePage, "[Home page](https://www2.compute.dtu.dk/courses/02105)"},
+		{"RegistrationSignUp", course.CourseAdditionalSection.RegistrationSignUp, "At the Studyplanner"},
+		{"GeneralObjectives", course.CourseObjectivesSection.GeneralObjectives,
+			"The course gives an introduction to fundamental algorithms and data structures."},
+		{"LearningObjectives.Intro", course.CourseLearningObjectivesSection.Intro,
+			"A student who has met the objectives of the course will be able to:"},
+		{"Content", course.CourseContentSection.Content, "Sorting, searching, graph algorithms and dynamic programming."},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+
+	wantObjectives := []string{
+		"Describe basic data structures such as stacks, queues and heaps.",
+		"Analyze the running time of algorithms.",
+	}
+	if got := course.CourseLearningObjectivesSection.Objectives; !slices.Equal(got, wantObjectives) {
+		t.Errorf("Objectives = %q, want %q", got, wantObjectives)
+	}
+
+	courseTypes := course.CourseTypeSection.CourseType
+	if len(courseTypes) != 2 || courseTypes[0].Title != "BSc" || courseTypes[1].Title != "Mandatory course in the BSc programme" {
+		t.Fatalf("CourseType = %+v, want BSc and the mandatory course block", courseTypes)
+	}
+	if got := courseTypes[1].Expansions; !slices.Equal(got, []string{"Software Technology"}) {
+		t.Errorf("CourseType expansions = %q, want the programme without \"see more\"", got)
+	}
+	if course.CourseAdditionalSection.FetchTime.IsZero() {
+		t.Error("FetchTime is not set")
+	}
+}
+
+func TestParseCourseDanish(t *testing.T) {
+	course := parseFixture(t, "course_02105_da.html")
+
+	for _, tc := range []struct {
+		field, got, want string
+	}{
+		{"Title", course.Title, "Algorithms and Data Structures 1"},
+		{"DanishTitle", course.DanishTitle, "Algoritmer og datastrukturer 1"},
+		{"LanguageOfInstruction", course.LanguageOfInstruction, "Engelsk"},
+		{"ECTS", course.ECTS, "7,5"},
+		{"Schedule", course.CourseScheduleSection.Schedule, "Forår F3A (tirs 8-12)"},
+		{"Location", course.CourseScheduleSection.Location, "Campus Lyngby"},
+		{"ScopeAndForm", course.CourseScheduleSection.ScopeAndForm, "Forelæsninger og øvelser"},
+		{"DurationOfCourse", course.CourseScheduleSection.DurationOfCourse, "13 uger"},
+		{"DateOfExamination", course.CourseExamSection.DateOfExamination, "[F3A](http://www.dtu.dk/Uddannelse/Eksamen)"},
+		{"TypeOfAssessment", course.CourseExamSection.TypeOfAssessment, "Skriftlig eksamen"},
+		{"ExamDuration", course.CourseExamSection.ExamDuration, "Skriftlig prøve: 4 timer"},
+		{"Aid", course.CourseExamSection.Aid, "Alle hjælpemidler - uden adgang til internettet"},
+		{"Evaluation", course.CourseExamSection.Evaluation, "7-trins skala , ekstern censur"},
+		{"NotApplicableTogetherWith", course.CourseAdditionalSection.NotApplicableTogetherWith, "[02326](/course/02326)"},
+		{"AcademicPrerequisites", course.CourseAdditionalSection.AcademicPrerequisites,
+			"[01017](/course/2025-2026/01017) og [02100](/course/02100) eller tilsvarende programmeringserfaring"},
+		{"Responsible", course.CourseResponsibleSection.Responsible, "Inge Li Gørtz , Lyngby Campus, Bygning 321, (mailto:inge@dtu.dk)"},
+		{"CourseCoResponsible", course.CourseResponsibleSection.CourseCoResponsible, "Philip Bille , Lyngby Campus, Bygning 321"},
+		{"Department", course.CourseAdditionalSection.Department,
+			"[01 Institut for Matematik og Computer Science](http://www.compute.dtu.dk)"},
+		{"HomePage", course.CourseAdditionalSection.HomePage, "[Hjemmeside](https://www2.compute.dtu.dk/courses/02105)"},
+		{"RegistrationSignUp", course.CourseAdditionalSection.RegistrationSignUp, "I Studieplanlæggeren"},
+		{"GeneralObjectives", course.CourseObjectivesSection.GeneralObjectives,
+			"Kurset giver en introduktion til grundlæggende algoritmer og datastrukturer."},
+		{"LearningObjectives.Intro", course.CourseLearningObjectivesSection.Intro,
+			"En studerende, der fuldt ud har opfyldt kursets mål, vil kunne:"},
+		{"Content", course.CourseContentSection.Content, "Sortering, søgning, grafalgoritmer og dynamisk programmering."},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+
+	if got := len(course.CourseLearningObjectivesSection.Objectives); got != 2 {
+		t.Errorf("got %d learning objectives, want 2", got)
+	}
+}
+
+func TestParseCourseWithoutInformation(t *testing.T) {
+	for name, page := range map[string]string{
+		"empty page":   "",
+		"no info box":  "<html><body><h2>Course not found</h2></body></html>",
+		"empty fields": `<div class="col-xs-8"><h2></h2></div><div class="box information"><table></table></div>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCourse(strings.NewReader(page), "02105"); !errors.Is(err, ErrNoCourseInformation) {
+				t.Errorf("error = %v, want ErrNoCourseInformation", err)
+			}
+		})
+	}
+
+	f, err := os.Open("testdata/course_not_found.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := ParseCourse(f, "99999"); !errors.Is(err, ErrNoCourseInformation) {
+		t.Errorf("recorded not-found page: error = %v, want ErrNoCourseInformation", err)
+	}
+}
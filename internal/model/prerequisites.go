@@ -0,0 +1,236 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CourseReferences is the structured form of a field that refers to other courses,
+// such as "Academic prerequisites".
+type CourseReferences struct {
+	Raw string
+	// Courses are the course numbers mentioned, in order of appearance.
+	Courses []string
+	// Remainder is the rest of the text, e.g. "Good knowledge of linear algebra".
+	Remainder string
+}
+
+var (
+	// courseLinkPattern matches Markdown links to a course page, e.g. "[01005](/course/01005)",
+	// optionally of a given academic year.
+	courseLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*/course/(?:\d{4}-\d{4}/)?([0-9A-Za-z]{5})[^)]*\)`)
+	// courseNumberTextPattern matches course numbers written out in the text.
+	courseNumberTextPattern = regexp.MustCompile(`\b\d{5}\b`)
+	// referenceSeparatorPattern matches the separators left behind when course numbers are removed.
+	referenceSeparatorPattern = regexp.MustCompile(`(?:\s*[,/;+&]\s*)+`)
+	// danglingConjunctionPattern matches conjunctions left at either end of the remainder.
+	danglingConjunctionPattern = regexp.MustCompile(`(?i)^(?:(?:and|or|og|eller)\b[\s,]*)+|(?:[\s,]*\b(?:and|or|og|eller))+$`)
+)
+
+// ParseCourseReferences extracts the course numbers from the text of a field,
+// keeping the text that is not a course number as the remainder.
+func ParseCourseReferences(text string) CourseReferences {
+	refs := CourseReferences{Raw: text}
+	seen := make(map[string]bool)
+	add := func(courseNumber string) {
+		courseNumber = strings.ToUpper(courseNumber)
+		if !seen[courseNumber] {
+			seen[courseNumber] = true
+			refs.Courses = append(refs.Courses, courseNumber)
+		}
+	}
+
+	// Linked courses are removed entirely; the link text is usually just the course number.
+	plain := courseLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		m := courseLinkPattern.FindStringSubmatch(link)
+		add(m[2])
+		return " "
+	})
+	plain = markdownLinkPattern.ReplaceAllString(plain, "$1")
+	for _, courseNumber := range courseNumberTextPattern.FindAllString(plain, -1) {
+		add(courseNumber)
+	}
+
+	remainder := courseNumberTextPattern.ReplaceAllString(plain, " ")
+	remainder = referenceSeparatorPattern.ReplaceAllString(remainder, ", ")
+	remainder = strings.Join(strings.Fields(remainder), " ")
+	remainder = strings.Trim(remainder, " ,.;/")
+	remainder = strings.Trim(danglingConjunctionPattern.ReplaceAllString(remainder, ""), " ,.;/")
+	// Only keep the remainder if there is more to it than punctuation.
+	if strings.IndexFunc(remainder, unicode.IsLetter) >= 0 {
+		refs.Remainder = remainder
+	}
+	return refs
+}
+
+// Prerequisites parses the academic prerequisites of the course.
+func (s CourseAdditionalSection) Prerequisites() CourseReferences {
+	return ParseCourseReferences(s.AcademicPrerequisites)
+}
+
+// Exclusions parses the courses the course is not applicable together with.
+func (s CourseAdditionalSection) Exclusions() CourseReferences {
+	return ParseCourseReferences(s.NotApplicableTogetherWith)
+}
+
+// PrerequisiteGraph links courses to the courses named in their academic prerequisites.
+type PrerequisiteGraph struct {
+	// Prerequisites maps a course number to the course numbers it requires. Only courses whose
+	// prerequisites are known have an entry, but they may name courses that are not known.
+	Prerequisites map[string][]string
+	// Titles maps the numbers of known courses to their titles.
+	Titles map[string]string
+}
+
+// NewPrerequisiteGraph returns an empty graph.
+func NewPrerequisiteGraph() *PrerequisiteGraph {
+	return &PrerequisiteGraph{
+		Prerequisites: make(map[string][]string),
+		Titles:        make(map[string]string),
+	}
+}
+
+// BuildPrerequisiteGraph builds the graph of the given courses.
+func BuildPrerequisiteGraph(courses []*Course) *PrerequisiteGraph {
+	graph := NewPrerequisiteGraph()
+	for _, course := range courses {
+		graph.Add(course)
+	}
+	return graph
+}
+
+// Add adds or replaces a course in the graph.
+func (g *PrerequisiteGraph) Add(course *Course) {
+	g.AddReferences(course.CourseNumber, course.Title, course.CourseAdditionalSection.Prerequisites().Courses)
+}
+
+// AddReferences adds or replaces a course in the graph, given the courses named in its prerequisites.
+func (g *PrerequisiteGraph) AddReferences(courseNumber, title string, prerequisites []string) {
+	g.Titles[courseNumber] = title
+	var required []string
+	for _, prerequisite := range prerequisites {
+		// A course occasionally links itself, e.g. in a note about an older version.
+		if prerequisite != courseNumber {
+			required = append(required, prerequisite)
+		}
+	}
+	g.Prerequisites[courseNumber] = required
+}
+
+// known reports whether the prerequisites of the course are known.
+func (g *PrerequisiteGraph) known(courseNumber string) bool {
+	_, ok := g.Prerequisites[courseNumber]
+	return ok
+}
+
+// Missing returns the courses reachable from the root whose prerequisites are not known yet, sorted.
+func (g *PrerequisiteGraph) Missing(root string) []string {
+	var missing []string
+	g.walk(root, func(courseNumber string) {
+		if !g.known(courseNumber) {
+			missing = append(missing, courseNumber)
+		}
+	})
+	sort.Strings(missing)
+	return missing
+}
+
+// walk visits every course reachable from the root once.
+func (g *PrerequisiteGraph) walk(root string, visit func(courseNumber string)) {
+	seen := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		courseNumber := queue[0]
+		queue = queue[1:]
+		visit(courseNumber)
+		for _, prerequisite := range g.Prerequisites[courseNumber] {
+			if !seen[prerequisite] {
+				seen[prerequisite] = true
+				queue = append(queue, prerequisite)
+			}
+		}
+	}
+}
+
+// label returns "number title" for known courses, and the number alone otherwise.
+func (g *PrerequisiteGraph) label(courseNumber string) string {
+	if title := g.Titles[courseNumber]; title != "" {
+		return courseNumber + " " + title
+	}
+	return courseNumber
+}
+
+// Tree renders the recursive prerequisites of the root as an indented tree. A course that has
+// already been expanded is marked "(see above)" instead of being repeated, and cycles are marked
+// "(cycle)". Courses whose own prerequisites are not known are marked "(not fetched)".
+func (g *PrerequisiteGraph) Tree(root string) string {
+	var sb strings.Builder
+	sb.WriteString(g.label(root) + "\n")
+	expanded := map[string]bool{root: true}
+	g.writeTree(&sb, root, "", map[string]bool{root: true}, expanded)
+	return sb.String()
+}
+
+func (g *PrerequisiteGraph) writeTree(sb *strings.Builder, courseNumber, indent string, path, expanded map[string]bool) {
+	prerequisites := g.Prerequisites[courseNumber]
+	for idx, prerequisite := range prerequisites {
+		branch, childIndent := "├─ ", indent+"│  "
+		if idx == len(prerequisites)-1 {
+			branch, childIndent = "└─ ", indent+"   "
+		}
+
+		line := indent + branch + g.label(prerequisite)
+		switch {
+		case path[prerequisite]:
+			sb.WriteString(line + " (cycle)\n")
+			continue
+		case expanded[prerequisite]:
+			if len(g.Prerequisites[prerequisite]) > 0 {
+				line += " (see above)"
+			}
+			sb.WriteString(line + "\n")
+			continue
+		case !g.known(prerequisite):
+			sb.WriteString(line + " (not fetched)\n")
+			continue
+		}
+
+		sb.WriteString(line + "\n")
+		expanded[prerequisite] = true
+		path[prerequisite] = true
+		g.writeTree(sb, prerequisite, childIndent, path, expanded)
+		delete(path, prerequisite)
+	}
+}
+
+// DOT renders the prerequisites reachable from the root as a Graphviz digraph,
+// with an edge from each prerequisite to the course that requires it.
+func (g *PrerequisiteGraph) DOT(root string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("digraph %q {\n", "prerequisites_"+root))
+	sb.WriteString("  rankdir=LR;\n  node [shape=box, style=rounded];\n")
+
+	var nodes []string
+	g.walk(root, func(courseNumber string) {
+		nodes = append(nodes, courseNumber)
+	})
+	for _, courseNumber := range nodes {
+		attrs := ""
+		if courseNumber == root {
+			attrs = ", style=\"rounded,bold\""
+		} else if !g.known(courseNumber) {
+			attrs = ", style=\"rounded,dashed\""
+		}
+		sb.WriteString(fmt.Sprintf("  %q [label=%q%s];\n", courseNumber, g.label(courseNumber), attrs))
+	}
+	for _, courseNumber := range nodes {
+		for _, prerequisite := range g.Prerequisites[courseNumber] {
+			sb.WriteString(fmt.Sprintf("  %q -> %q;\n", prerequisite, courseNumber))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
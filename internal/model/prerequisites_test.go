@@ -0,0 +1,119 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCourseReferences(t *testing.T) {
+	for _, tc := range []struct {
+		name, text string
+		courses    []string
+		remainder  string
+	}{
+		{"linked number", "[01005](/course/01005)", []string{"01005"}, ""},
+		{"year-qualified links", "[01005](/course/2025-2026/01005), [01006](https://kurser.dtu.dk/course/2024-2025/01006)",
+			[]string{"01005", "01006"}, ""},
+		{"alternatives or equivalent", "01005/01006 or equivalent", []string{"01005", "01006"}, "equivalent"},
+		{"Danish og/eller", "[01017](/course/01017) og [02100](/course/02100) eller tilsvarende",
+			[]string{"01017", "02100"}, "tilsvarende"},
+		{"list of numbers", "02102, 02105, 02110", []string{"02102", "02105", "02110"}, ""},
+		{"conjunction only", "02105 and 02100", []string{"02105", "02100"}, ""},
+		{"duplicates", "[02105](/course/02105) or 02105", []string{"02105"}, ""},
+		{"lower-case link", "[4210x](/course/4210x)", []string{"4210X"}, ""},
+		{"remainder only", "Good knowledge of linear algebra", nil, "Good knowledge of linear algebra"},
+		{"other links keep their text", "[Course 10020](/course/10020) and [a link](https://example.com)",
+			[]string{"10020"}, "a link"},
+		{"not five digits", "123456 and 1234", nil, "123456 and 1234"},
+		{"empty", "", nil, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			refs := ParseCourseReferences(tc.text)
+			if !slices.Equal(refs.Courses, tc.courses) {
+				t.Errorf("Courses = %q, want %q", refs.Courses, tc.courses)
+			}
+			if refs.Remainder != tc.remainder {
+				t.Errorf("Remainder = %q, want %q", refs.Remainder, tc.remainder)
+			}
+			if refs.Raw != tc.text {
+				t.Errorf("Raw = %q, want the input", refs.Raw)
+			}
+		})
+	}
+}
+
+func TestPrerequisiteGraphIgnoresSelfReferences(t *testing.T) {
+	course := &Course{CourseNumber: "02110", Title: "Algorithms 2"}
+	course.CourseAdditionalSection.AcademicPrerequisites = "[02105](/course/02105). Replaces [02110](/course/2024-2025/02110)"
+
+	graph := BuildPrerequisiteGraph([]*Course{course})
+	if got := graph.Prerequisites["02110"]; !slices.Equal(got, []string{"02105"}) {
+		t.Errorf("Prerequisites = %q, want [02105] without the course itself", got)
+	}
+}
+
+// testGraph is 02110, which requires 02105 and 02106. Both require 01017, which requires 01001,
+// and 02105 also requires 02100, which has not been fetched.
+func testGraph() *PrerequisiteGraph {
+	graph := NewPrerequisiteGraph()
+	graph.AddReferences("02110", "Algorithms 2", []string{"02105", "02106"})
+	graph.AddReferences("02105", "Algorithms 1", []string{"01017", "02100"})
+	graph.AddReferences("02106", "Software Engineering", []string{"01017"})
+	graph.AddReferences("01017", "Discrete Mathematics", []string{"01001"})
+	graph.AddReferences("01001", "Mathematics 1a", nil)
+	return graph
+}
+
+func TestPrerequisiteGraphTree(t *testing.T) {
+	want := strings.Join([]string{
+		"02110 Algorithms 2",
+		"├─ 02105 Algorithms 1",
+		"│  ├─ 01017 Discrete Mathematics",
+		"│  │  └─ 01001 Mathematics 1a",
+		"│  └─ 02100 (not fetched)",
+		"└─ 02106 Software Engineering",
+		"   └─ 01017 Discrete Mathematics (see above)",
+	}, "\n") + "\n"
+	if got := testGraph().Tree("02110"); got != want {
+		t.Errorf("Tree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrerequisiteGraphTreeCycle(t *testing.T) {
+	graph := NewPrerequisiteGraph()
+	graph.AddReferences("10020", "Physics 1", []string{"10022"})
+	graph.AddReferences("10022", "Physics 2", []string{"10020"})
+
+	want := "10020 Physics 1\n└─ 10022 Physics 2\n   └─ 10020 Physics 1 (cycle)\n"
+	if got := graph.Tree("10020"); got != want {
+		t.Errorf("Tree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrerequisiteGraphMissing(t *testing.T) {
+	if got := testGraph().Missing("02110"); !slices.Equal(got, []string{"02100"}) {
+		t.Errorf("Missing() = %q, want [02100]", got)
+	}
+	if got := testGraph().Missing("01017"); len(got) != 0 {
+		t.Errorf("Missing(01017) = %q, want none", got)
+	}
+}
+
+func TestPrerequisiteGraphDOT(t *testing.T) {
+	want := `digraph "prerequisites_02105" {
+  rankdir=LR;
+  node [shape=box, style=rounded];
+  "02105" [label="02105 Algorithms 1", style="rounded,bold"];
+  "01017" [label="01017 Discrete Mathematics"];
+  "02100" [label="02100", style="rounded,dashed"];
+  "01001" [label="01001 Mathematics 1a"];
+  "01017" -> "02105";
+  "02100" -> "02105";
+  "01001" -> "01017";
+}
+`
+	if got := testGraph().DOT("02105"); got != want {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// referenceIndexFile is where the course references of every fetched course are stored.
+const referenceIndexFile = "data/course_references.json"
+
+// referenceIndexSaveDelay is how long changes from single fetches are collected before the
+// index is written, so a burst of fetches rewrites the file once rather than once per course.
+const referenceIndexSaveDelay = 30 * time.Second
+
+// courseReferenceEntry is what the index keeps of a course.
+type courseReferenceEntry struct {
+	Title         string   `json:"title"`
+	Prerequisites []string `json:"prerequisites,omitempty"`
+	Exclusions    []string `json:"exclusions,omitempty"`
+}
+
+// ReferenceIndex answers which courses name a given course as a prerequisite, or as a course
+// they are not applicable together with. The references of each fetched course are persisted,
+// and the reverse lookups are rebuilt from them in memory.
+type ReferenceIndex struct {
+	path string
+
+	mu      sync.RWMutex
+	courses map[string]courseReferenceEntry
+	// requiredBy maps a course number to the courses that name it as a prerequisite.
+	requiredBy map[string][]string
+	// excludedBy maps a course number to the courses that are not applicable together with it.
+	excludedBy map[string][]string
+	// dirty is set when the index has changes that have not been written yet.
+	dirty bool
+	// saveTimer is the pending delayed save, if any.
+	saveTimer *time.Timer
+}
+
+// NewReferenceIndex loads the index from path. A missing file gives an empty index.
+func NewReferenceIndex(path string) (*ReferenceIndex, error) {
+	index := &ReferenceIndex{
+		path:    path,
+		courses: make(map[string]courseReferenceEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &index.courses); err != nil {
+			return nil, err
+		}
+	}
+	index.rebuildLocked()
+	return index, nil
+}
+
+// Update records the references of the courses, replacing what was known about them,
+// and persists the index if anything changed, including changes recorded earlier.
+func (x *ReferenceIndex) Update(courses ...*Course) error {
+	x.Record(courses...)
+	return x.Save()
+}
+
+// Record updates the references of the courses in memory only. It reports whether anything
+// changed; the changes are written by the next Save.
+func (x *ReferenceIndex) Record(courses ...*Course) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	changed := false
+	for _, course := range courses {
+		entry := courseReferenceEntry{
+			Title:         course.Title,
+			Prerequisites: course.CourseAdditionalSection.Prerequisites().Courses,
+			Exclusions:    course.CourseAdditionalSection.Exclusions().Courses,
+		}
+		if existing, ok := x.courses[course.CourseNumber]; ok && existing.Title == entry.Title &&
+			slices.Equal(existing.Prerequisites, entry.Prerequisites) && slices.Equal(existing.Exclusions, entry.Exclusions) {
+			continue
+		}
+		x.courses[course.CourseNumber] = entry
+		changed = true
+	}
+	if changed {
+		x.rebuildLocked()
+		x.dirty = true
+	}
+	return changed
+}
+
+// Save writes the index if it has unsaved changes.
+func (x *ReferenceIndex) Save() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.saveTimer != nil {
+		x.saveTimer.Stop()
+		x.saveTimer = nil
+	}
+	if !x.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(x.courses, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(x.path, data); err != nil {
+		return err
+	}
+	x.dirty = false
+	return nil
+}
+
+// saveLater saves the index after referenceIndexSaveDelay, unless a save is already pending.
+func (x *ReferenceIndex) saveLater() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.saveTimer != nil {
+		return
+	}
+	x.saveTimer = time.AfterFunc(referenceIndexSaveDelay, func() {
+		x.mu.Lock()
+		x.saveTimer = nil
+		x.mu.Unlock()
+		if err := x.Save(); err != nil {
+			log.Println("Error saving the reference index:", err)
+		}
+	})
+}
+
+// rebuildLocked recomputes the reverse lookups. x.mu must be held for writing.
+func (x *ReferenceIndex) rebuildLocked() {
+	x.requiredBy = make(map[string][]string)
+	x.excludedBy = make(map[string][]string)
+	for courseNumber, entry := range x.courses {
+		for _, prerequisite := range entry.Prerequisites {
+			if prerequisite != courseNumber {
+				x.requiredBy[prerequisite] = append(x.requiredBy[prerequisite], courseNumber)
+			}
+		}
+		for _, exclusion := range entry.Exclusions {
+			if exclusion != courseNumber {
+				x.excludedBy[exclusion] = append(x.excludedBy[exclusion], courseNumber)
+			}
+		}
+	}
+	for _, lookup := range []map[string][]string{x.requiredBy, x.excludedBy} {
+		for _, courseNumbers := range lookup {
+			sort.Strings(courseNumbers)
+		}
+	}
+}
+
+// Unlocks returns the known courses that name the course as a prerequisite, sorted.
+func (x *ReferenceIndex) Unlocks(courseNumber string) []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return slices.Clone(x.requiredBy[courseNumber])
+}
+
+// MutuallyExclusive returns the courses that cannot be taken together with the course, sorted.
+// Either course may be the one naming the other, so both directions are included.
+func (x *ReferenceIndex) MutuallyExclusive(courseNumber string) []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	courseNumbers := slices.Clone(x.excludedBy[courseNumber])
+	for _, exclusion := range x.courses[courseNumber].Exclusions {
+		if exclusion != courseNumber && !slices.Contains(courseNumbers, exclusion) {
+			courseNumbers = append(courseNumbers, exclusion)
+		}
+	}
+	sort.Strings(courseNumbers)
+	return courseNumbers
+}
+
+// PrerequisiteGraph returns the prerequisite graph of every indexed course.
+func (x *ReferenceIndex) PrerequisiteGraph() *PrerequisiteGraph {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	graph := NewPrerequisiteGraph()
+	for courseNumber, entry := range x.courses {
+		graph.AddReferences(courseNumber, entry.Title, entry.Prerequisites)
+	}
+	return graph
+}
+
+// Title returns the title of an indexed course.
+func (x *ReferenceIndex) Title(courseNumber string) (string, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	entry, ok := x.courses[courseNumber]
+	return entry.Title, ok
+}
+
+// Len returns the number of indexed courses.
+func (x *ReferenceIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.courses)
+}
+
+var (
+	referenceIndexMu sync.Mutex
+	referenceIndex   *ReferenceIndex
+)
+
+// SetReferenceIndex overrides the index returned by GetReferenceIndex.
+func SetReferenceIndex(index *ReferenceIndex) {
+	referenceIndexMu.Lock()
+	defer referenceIndexMu.Unlock()
+	referenceIndex = index
+}
+
+// GetReferenceIndex returns the course reference index, loading it on first use.
+// A new index is seeded with the courses already in the cache.
+func GetReferenceIndex() (*ReferenceIndex, error) {
+	referenceIndexMu.Lock()
+	defer referenceIndexMu.Unlock()
+
+	if referenceIndex != nil {
+		return referenceIndex, nil
+	}
+	_, statErr := os.Stat(referenceIndexFile)
+	index, err := NewReferenceIndex(referenceIndexFile)
+	if err != nil {
+		return nil, err
+	}
+	referenceIndex = index
+	if !os.IsNotExist(statErr) {
+		return index, nil
+	}
+	known, err := KnownCourses()
+	if err != nil {
+		log.Println("Error loading known courses for the reference index:", err)
+		return index, nil
+	}
+	if err := index.Update(known...); err != nil {
+		log.Println("Error saving the reference index:", err)
+	}
+	return index, nil
+}
+
+// indexCourseReferences records a freshly fetched course in the reference index. The index is
+// only written after a delay, so the many fetches of a crawl do not each rewrite the file.
+// Only the current version of a course is indexed, so older years do not hide newer requirements.
+func indexCourseReferences(course *Course) {
+	if course.AcademicYear != CurrentAcademicYear(time.Now()) {
+		return
+	}
+	index, err := GetReferenceIndex()
+	if err != nil {
+		log.Println("Error loading the reference index:", err)
+		return
+	}
+	if index.Record(course) {
+		index.saveLater()
+	}
+}
+
+// SaveReferenceIndex writes any unsaved changes of the reference index, e.g. before shutting down.
+// It does nothing if the index has not been loaded.
+func SaveReferenceIndex() error {
+	referenceIndexMu.Lock()
+	index := referenceIndex
+	referenceIndexMu.Unlock()
+	if index == nil {
+		return nil
+	}
+	return index.Save()
+}
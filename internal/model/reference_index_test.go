@@ -0,0 +1,53 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReferenceIndexRecordKeepsChangesInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "course_references.json")
+	index, err := NewReferenceIndex(path)
+	if err != nil {
+		t.Fatalf("NewReferenceIndex: %v", err)
+	}
+	course := &Course{CourseNumber: "02110", Title: "Algorithms 2"}
+	course.CourseAdditionalSection.AcademicPrerequisites = "[02105](/course/02105)"
+
+	if !index.Record(course) {
+		t.Fatal("Record reported no change for a new course")
+	}
+	if got := index.Unlocks("02105"); !slices.Equal(got, []string{"02110"}) {
+		t.Errorf("Unlocks(02105) = %q, want [02110]", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Record wrote %s, want it written only by Save", path)
+	}
+	if index.Record(course) {
+		t.Error("Record reported a change for an unchanged course")
+	}
+
+	if err := index.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	reloaded, err := NewReferenceIndex(path)
+	if err != nil {
+		t.Fatalf("NewReferenceIndex after Save: %v", err)
+	}
+	if got := reloaded.Unlocks("02105"); !slices.Equal(got, []string{"02110"}) {
+		t.Errorf("Unlocks(02105) after reload = %q, want [02110]", got)
+	}
+
+	// A clean index is not written again.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Save rewrote %s without any changes", path)
+	}
+}
@@ -0,0 +1,276 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Semester is the DTU semester a schedule slot belongs to.
+type Semester string
+
+const (
+	SemesterAutumn Semester = "Autumn"
+	SemesterSpring Semester = "Spring"
+)
+
+// PeriodKind tells whether a slot is part of a 13-week or a 3-week teaching period.
+type PeriodKind string
+
+const (
+	Period13Week PeriodKind = "13-week"
+	Period3Week  PeriodKind = "3-week"
+)
+
+// ScheduleSlot is one weekly time slot in which a course is taught.
+type ScheduleSlot struct {
+	Semester Semester
+	Period   PeriodKind
+	Weekday  time.Weekday
+	// Start and End are the offsets from midnight, e.g. 8h and 12h.
+	Start time.Duration
+	End   time.Duration
+	// Block is the DTU block code, e.g. "E3A", or the name of the 3-week period, e.g. "January".
+	Block string
+}
+
+// String formats the slot as e.g. "E3A: Tue 08:00-12:00".
+func (s ScheduleSlot) String() string {
+	return fmt.Sprintf("%s: %s %s-%s", s.Block, s.Weekday.String()[:3], FormatClock(s.Start), FormatClock(s.End))
+}
+
+// FormatClock formats an offset from midnight as "08:00".
+func FormatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// blockTime is the weekday and time of a block in the 13-week periods.
+type blockTime struct {
+	weekday    time.Weekday
+	start, end time.Duration
+}
+
+// dtuBlocks is DTU's weekly block structure, which is the same in both semesters.
+// "E3A" is block 3A in the autumn (Efterår) semester, "F3A" the same block in spring (Forår).
+var dtuBlocks = map[string]blockTime{
+	"1A": {time.Monday, 8 * time.Hour, 12 * time.Hour},
+	"2A": {time.Monday, 13 * time.Hour, 17 * time.Hour},
+	"3A": {time.Tuesday, 8 * time.Hour, 12 * time.Hour},
+	"4A": {time.Tuesday, 13 * time.Hour, 17 * time.Hour},
+	"5A": {time.Wednesday, 8 * time.Hour, 12 * time.Hour},
+	"5B": {time.Wednesday, 13 * time.Hour, 17 * time.Hour},
+	"2B": {time.Thursday, 8 * time.Hour, 12 * time.Hour},
+	"1B": {time.Thursday, 13 * time.Hour, 17 * time.Hour},
+	"4B": {time.Friday, 8 * time.Hour, 12 * time.Hour},
+	"3B": {time.Friday, 13 * time.Hour, 17 * time.Hour},
+}
+
+// BlockCodes is the reference table of every E/F block code, e.g. "E1A" or "F5B".
+var BlockCodes = buildBlockCodes()
+
+func buildBlockCodes() map[string]ScheduleSlot {
+	codes := make(map[string]ScheduleSlot, 2*len(dtuBlocks))
+	for block, t := range dtuBlocks {
+		for prefix, semester := range map[string]Semester{"E": SemesterAutumn, "F": SemesterSpring} {
+			codes[prefix+block] = ScheduleSlot{
+				Semester: semester,
+				Period:   Period13Week,
+				Weekday:  t.weekday,
+				Start:    t.start,
+				End:      t.end,
+				Block:    prefix + block,
+			}
+		}
+	}
+	return codes
+}
+
+// ThreeWeekPeriods are the canonical names of the 3-week periods, in calendar order of the academic year.
+var ThreeWeekPeriods = []string{"January", "June", "July", "August"}
+
+// threeWeekPeriods maps the English and Danish names of the 3-week periods to their
+// canonical name and the semester they belong to.
+var threeWeekPeriods = map[string]struct {
+	name     string
+	semester Semester
+}{
+	"january": {"January", SemesterAutumn},
+	"januar":  {"January", SemesterAutumn},
+	"june":    {"June", SemesterSpring},
+	"juni":    {"June", SemesterSpring},
+	"july":    {"July", SemesterSpring},
+	"juli":    {"July", SemesterSpring},
+	"august":  {"August", SemesterSpring},
+}
+
+// 3-week courses are taught full time, every weekday.
+const (
+	threeWeekStart = 8 * time.Hour
+	threeWeekEnd   = 17 * time.Hour
+)
+
+// explicitTimeExpr captures a weekday abbreviation, the start hour and minutes, and the end hour and minutes.
+const explicitTimeExpr = `\b(mon|tue|wed|thu|fri|man|tir|ons|tor|fre)[a-zæøå]*\.?\s+(\d{1,2})(?:[.:](\d{2}))?\s*-\s*(\d{1,2})(?:[.:](\d{2}))?`
+
+var (
+	// blockCodePattern matches codes like "E3A", "F2B" and whole blocks like "E1" (both E1A and E1B).
+	blockCodePattern = regexp.MustCompile(`\b([EF])([1-5])([AB])?\b`)
+	// periodPattern matches the names of the 3-week periods.
+	periodPattern = regexp.MustCompile(`(?i)\b(january|januar|june|juni|july|juli|august)\b`)
+	// explicitTimePattern matches explicit times like "Wed 8-12" or "Tirsdag 13.00-17.00".
+	explicitTimePattern = regexp.MustCompile(`(?i)` + explicitTimeExpr)
+	// explicitAfterCodePattern matches an explicit time in parentheses right after a block code.
+	explicitAfterCodePattern = regexp.MustCompile(`(?i)^\s*\(\s*` + explicitTimeExpr + `\s*\)`)
+	// markdownLinkPattern matches Markdown links, whose URLs must not be parsed as schedule text.
+	markdownLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	// semesterPattern matches semester names in free text.
+	semesterPattern = regexp.MustCompile(`(?i)\b(autumn|efterår|spring|forår)\b`)
+)
+
+var explicitWeekdays = map[string]time.Weekday{
+	"mon": time.Monday, "man": time.Monday,
+	"tue": time.Tuesday, "tir": time.Tuesday,
+	"wed": time.Wednesday, "ons": time.Wednesday,
+	"thu": time.Thursday, "tor": time.Thursday,
+	"fri": time.Friday, "fre": time.Friday,
+}
+
+// ParsedSchedule is the structured form of a course's schedule text.
+type ParsedSchedule struct {
+	Raw   string
+	Slots []ScheduleSlot
+}
+
+// Blocks returns the distinct block codes and period names of the schedule, sorted.
+func (p ParsedSchedule) Blocks() []string {
+	seen := make(map[string]bool)
+	var blocks []string
+	for _, slot := range p.Slots {
+		if !seen[slot.Block] {
+			seen[slot.Block] = true
+			blocks = append(blocks, slot.Block)
+		}
+	}
+	sort.Strings(blocks)
+	return blocks
+}
+
+// ParseSchedule turns schedule text such as "Spring E3A (Tue 8-12)", "F2B" or
+// "Autumn E1 and January" into weekly time slots. Block codes are looked up in BlockCodes,
+// unless the code is directly followed by an explicit time in parentheses.
+// Other explicit times like "Wed 8-12" are only used when the text contains no block codes.
+// Text that cannot be interpreted results in a schedule without slots.
+func ParseSchedule(schedule string) ParsedSchedule {
+	parsed := ParsedSchedule{Raw: schedule}
+	text := markdownLinkPattern.ReplaceAllString(schedule, "$1")
+	seen := make(map[string]bool)
+	add := func(slot ScheduleSlot) {
+		key := fmt.Sprintf("%s/%s/%d/%d", slot.Semester, slot.Block, slot.Weekday, slot.Start)
+		if !seen[key] {
+			seen[key] = true
+			parsed.Slots = append(parsed.Slots, slot)
+		}
+	}
+
+	for _, loc := range blockCodePattern.FindAllStringSubmatchIndex(text, -1) {
+		prefix, block, half := text[loc[2]:loc[3]], text[loc[4]:loc[5]], ""
+		if loc[6] >= 0 {
+			half = text[loc[6]:loc[7]]
+		}
+
+		if half == "" {
+			// A whole block, e.g. "E1", covers both halves.
+			for _, h := range []string{"A", "B"} {
+				add(BlockCodes[prefix+block+h])
+			}
+			continue
+		}
+
+		slot := BlockCodes[prefix+block+half]
+		// The page sometimes spells out the time, e.g. "E3A (Tue 8-12)". That is what students
+		// see on the course page, so it takes precedence over the reference table.
+		if m := explicitAfterCodePattern.FindStringSubmatch(text[loc[1]:]); m != nil {
+			if start, end := clock(m[2], m[3]), clock(m[4], m[5]); end > start {
+				slot.Weekday = explicitWeekdays[strings.ToLower(m[1])]
+				slot.Start, slot.End = start, end
+			}
+		}
+		add(slot)
+	}
+
+	for _, m := range periodPattern.FindAllStringSubmatch(text, -1) {
+		period := threeWeekPeriods[strings.ToLower(m[1])]
+		for day := time.Monday; day <= time.Friday; day++ {
+			add(ScheduleSlot{
+				Semester: period.semester,
+				Period:   Period3Week,
+				Weekday:  day,
+				Start:    threeWeekStart,
+				End:      threeWeekEnd,
+				Block:    period.name,
+			})
+		}
+	}
+
+	if len(parsed.Slots) > 0 && hasBlockCode(parsed.Slots) {
+		return parsed
+	}
+
+	// No block codes, so fall back to explicit weekdays and times in the text.
+	semester := semesterOf(text)
+	for _, m := range explicitTimePattern.FindAllStringSubmatch(text, -1) {
+		start, end := clock(m[2], m[3]), clock(m[4], m[5])
+		if end <= start {
+			continue
+		}
+		add(ScheduleSlot{
+			Semester: semester,
+			Period:   Period13Week,
+			Weekday:  explicitWeekdays[strings.ToLower(m[1])],
+			Start:    start,
+			End:      end,
+			Block:    strings.TrimSpace(m[0]),
+		})
+	}
+
+	return parsed
+}
+
+// ParsedSchedule parses the schedule text of the section.
+func (s CourseScheduleSection) ParsedSchedule() ParsedSchedule {
+	return ParseSchedule(s.Schedule)
+}
+
+// hasBlockCode reports whether any of the slots come from a 13-week block code.
+func hasBlockCode(slots []ScheduleSlot) bool {
+	for _, slot := range slots {
+		if slot.Period == Period13Week {
+			return true
+		}
+	}
+	return false
+}
+
+// semesterOf returns the semester named in the text, or an empty Semester if there is none.
+func semesterOf(text string) Semester {
+	m := semesterPattern.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	switch strings.ToLower(m[1]) {
+	case "autumn", "efterår":
+		return SemesterAutumn
+	default:
+		return SemesterSpring
+	}
+}
+
+// clock converts an hour and optional minutes to an offset from midnight.
+func clock(hours, minutes string) time.Duration {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// slotStrings formats slots as "semester/period/block: weekday start-end" for comparison.
+func slotStrings(slots []ScheduleSlot) []string {
+	var out []string
+	for _, slot := range slots {
+		out = append(out, fmt.Sprintf("%s/%s/%s", slot.Semester, slot.Period, slot))
+	}
+	return out
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		schedule string
+		want     []string
+	}{
+		{"E3A (Tues 8-12)", []string{"Autumn/13-week/E3A: Tue 08:00-12:00"}},
+		{"F2B (Thurs 8-12)", []string{"Spring/13-week/F2B: Thu 08:00-12:00"}},
+		{"E1A", []string{"Autumn/13-week/E1A: Mon 08:00-12:00"}},
+		// A whole block covers both halves.
+		{"F4 (Tues 13-17, Fri 8-12)", []string{
+			"Spring/13-week/F4A: Tue 13:00-17:00",
+			"Spring/13-week/F4B: Fri 08:00-12:00",
+		}},
+		{"E5A (Wed 8-12) and E5B (Wed 13-17)", []string{
+			"Autumn/13-week/E5A: Wed 08:00-12:00",
+			"Autumn/13-week/E5B: Wed 13:00-17:00",
+		}},
+		// An explicit time right after the code is what the page shows, so it wins over the table.
+		{"E3A (Tue 9.15-12.00)", []string{"Autumn/13-week/E3A: Tue 09:15-12:00"}},
+		{"Efterår E2A (man 13-17)", []string{"Autumn/13-week/E2A: Mon 13:00-17:00"}},
+		{"Forår F3B (fre 13-17)", []string{"Spring/13-week/F3B: Fri 13:00-17:00"}},
+		// Links are reduced to their text, so codes in URLs are ignored.
+		{"[E2B](https://www.dtu.dk/F4A)", []string{"Autumn/13-week/E2B: Thu 08:00-12:00"}},
+		{"E2B and January", []string{
+			"Autumn/13-week/E2B: Thu 08:00-12:00",
+			"Autumn/3-week/January: Mon 08:00-17:00",
+			"Autumn/3-week/January: Tue 08:00-17:00",
+			"Autumn/3-week/January: Wed 08:00-17:00",
+			"Autumn/3-week/January: Thu 08:00-17:00",
+			"Autumn/3-week/January: Fri 08:00-17:00",
+		}},
+		{"Juni", []string{
+			"Spring/3-week/June: Mon 08:00-17:00",
+			"Spring/3-week/June: Tue 08:00-17:00",
+			"Spring/3-week/June: Wed 08:00-17:00",
+			"Spring/3-week/June: Thu 08:00-17:00",
+			"Spring/3-week/June: Fri 08:00-17:00",
+		}},
+		// Without block codes, explicit weekdays and times are used, with the semester if named.
+		{"Autumn: Tue 13-17", []string{"Autumn/13-week/Tue 13-17: Tue 13:00-17:00"}},
+		{"Wed 8-12 and Fri 13.15-17", []string{
+			"/13-week/Wed 8-12: Wed 08:00-12:00",
+			"/13-week/Fri 13.15-17: Fri 13:15-17:00",
+		}},
+		// Explicit times elsewhere in the text are ignored when there are block codes.
+		{"E4A, exercises Wed 8-10", []string{"Autumn/13-week/E4A: Tue 13:00-17:00"}},
+		// Codes outside DTU's block structure are ignored, so the explicit time is used instead.
+		{"E7 (Wed 18-22)", []string{"/13-week/Wed 18-22: Wed 18:00-22:00"}},
+		// Text without a schedule gives no slots.
+		{"Agreed upon with the supervisor", nil},
+		{"Fri 17-13", nil},
+		{"", nil},
+	} {
+		t.Run(tc.schedule, func(t *testing.T) {
+			parsed := ParseSchedule(tc.schedule)
+			if got := slotStrings(parsed.Slots); !slices.Equal(got, tc.want) {
+				t.Errorf("ParseSchedule(%q) =\n%q\nwant\n%q", tc.schedule, got, tc.want)
+			}
+			if parsed.Raw != tc.schedule {
+				t.Errorf("Raw = %q, want the input", parsed.Raw)
+			}
+		})
+	}
+}
+
+func TestParseScheduleDeduplicates(t *testing.T) {
+	parsed := ParseSchedule("E3A (Tue 8-12), E3A and E3A")
+	if len(parsed.Slots) != 1 {
+		t.Errorf("got %d slots, want 1: %q", len(parsed.Slots), slotStrings(parsed.Slots))
+	}
+}
+
+func TestParsedScheduleBlocks(t *testing.T) {
+	got := ParseSchedule("January and F4 (Tues 13-17, Fri 8-12)").Blocks()
+	want := []string{"F4A", "F4B", "January"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Blocks() = %q, want %q", got, want)
+	}
+}
+
+func TestBlockCodes(t *testing.T) {
+	if len(BlockCodes) != 20 {
+		t.Errorf("got %d block codes, want 10 blocks in each semester", len(BlockCodes))
+	}
+	for code, slot := range BlockCodes {
+		if slot.Block != code || slot.End-slot.Start != 4*time.Hour || slot.Weekday < time.Monday || slot.Weekday > time.Friday {
+			t.Errorf("BlockCodes[%q] = %+v is not a 4-hour weekday block", code, slot)
+		}
+	}
+	if slot := BlockCodes["F5B"]; slot.Semester != SemesterSpring || slot.Weekday != time.Wednesday || slot.Start != 13*time.Hour {
+		t.Errorf("F5B = %+v, want spring Wednesday afternoon", slot)
+	}
+}
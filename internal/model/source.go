@@ -0,0 +1,141 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Names of the available course sources, as used in config.Config.CourseSource.
+const (
+	SourceChromedp = "chromedp"
+	SourceHTTP     = "http"
+)
+
+// CourseSource fetches the HTML page of a course and returns it as a goquery Document.
+type CourseSource interface {
+	Name() string
+	FetchPage(ctx context.Context, url string) (*goquery.Document, error)
+}
+
+// ChromedpSource renders the course page in a headless Chrome.
+// It is slow, but sees the page exactly as a user would.
+type ChromedpSource struct {
+	// Pool is the shared browser to render in. If nil, a browser is launched per fetch.
+	Pool *utils.BrowserPool
+}
+
+func (ChromedpSource) Name() string {
+	return SourceChromedp
+}
+
+func (c ChromedpSource) FetchPage(ctx context.Context, url string) (*goquery.Document, error) {
+	if c.Pool != nil {
+		return c.Pool.FetchPage(ctx, url)
+	}
+	return utils.FetchDynamicCoursePage(ctx, url)
+}
+
+// HTTPSource fetches the server-rendered course page with a plain HTTP request.
+// It does not need Chrome, which makes it suitable for small hosts.
+type HTTPSource struct{}
+
+func (HTTPSource) Name() string {
+	return SourceHTTP
+}
+
+func (HTTPSource) FetchPage(ctx context.Context, url string) (*goquery.Document, error) {
+	return utils.FetchStaticCoursePage(ctx, url)
+}
+
+// FallbackSource tries each of its sources in order until one succeeds.
+type FallbackSource struct {
+	Sources []CourseSource
+}
+
+func (f FallbackSource) Name() string {
+	names := make([]string, len(f.Sources))
+	for i, src := range f.Sources {
+		names[i] = src.Name()
+	}
+	return strings.Join(names, "->")
+}
+
+func (f FallbackSource) FetchPage(ctx context.Context, url string) (*goquery.Document, error) {
+	var errs []error
+	for _, src := range f.Sources {
+		doc, err := src.FetchPage(ctx, url)
+		if err == nil {
+			return doc, nil
+		}
+		log.Printf("Course source %s failed for %s: %v", src.Name(), url, err)
+		errs = append(errs, fmt.Errorf("%s: %w", src.Name(), err))
+
+		// No point in trying the next source if the caller gave up.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// NewCourseSource returns the source with the given name, falling back to the other one on failure.
+// Chromedp fetches are rendered in the given pool, which may be nil.
+func NewCourseSource(name string, pool *utils.BrowserPool) (CourseSource, error) {
+	chrome := ChromedpSource{Pool: pool}
+	switch name {
+	case SourceChromedp:
+		return FallbackSource{Sources: []CourseSource{chrome, HTTPSource{}}}, nil
+	case SourceHTTP:
+		return FallbackSource{Sources: []CourseSource{HTTPSource{}, chrome}}, nil
+	default:
+		return nil, fmt.Errorf("unknown course source %q", name)
+	}
+}
+
+var (
+	courseSourceMu sync.RWMutex
+	courseSource   CourseSource
+)
+
+// SetCourseSource overrides the source used by FetchCourse, e.g. to use a local stand-in in tests.
+func SetCourseSource(src CourseSource) {
+	courseSourceMu.Lock()
+	defer courseSourceMu.Unlock()
+	courseSource = src
+}
+
+// getCourseSource returns the configured source, creating it from config.GlobalConfig on first use.
+func getCourseSource() CourseSource {
+	courseSourceMu.RLock()
+	src := courseSource
+	courseSourceMu.RUnlock()
+	if src != nil {
+		return src
+	}
+
+	src = NewConfiguredCourseSource(nil)
+	SetCourseSource(src)
+	return src
+}
+
+// NewConfiguredCourseSource builds the source selected in config.GlobalConfig.
+func NewConfiguredCourseSource(pool *utils.BrowserPool) CourseSource {
+	name := SourceChromedp
+	if config.GlobalConfig != nil && config.GlobalConfig.CourseSource != "" {
+		name = config.GlobalConfig.CourseSource
+	}
+	src, err := NewCourseSource(name, pool)
+	if err != nil {
+		log.Printf("Invalid course source: %v, using %s", err, SourceChromedp)
+		src, _ = NewCourseSource(SourceChromedp, pool)
+	}
+	return src
+}
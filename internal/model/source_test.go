@@ -0,0 +1,120 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// stubSource is a CourseSource that returns a fixed page or error and counts its calls.
+// If release is set, fetches wait until it is closed.
+type stubSource struct {
+	name    string
+	html    string
+	err     error
+	release chan struct{}
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *stubSource) Name() string {
+	return s.name
+}
+
+func (s *stubSource) FetchPage(ctx context.Context, url string) (*goquery.Document, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	if s.release != nil {
+		<-s.release
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return goquery.NewDocumentFromReader(strings.NewReader(s.html))
+}
+
+func TestFallbackSourcePrimarySucceeds(t *testing.T) {
+	primary := &stubSource{name: "primary", html: "<p>primary</p>"}
+	secondary := &stubSource{name: "secondary", html: "<p>secondary</p>"}
+	src := FallbackSource{Sources: []CourseSource{primary, secondary}}
+
+	doc, err := src.FetchPage(context.Background(), "https://example.com/course/02105")
+	if err != nil {
+		t.Fatalf("FetchPage: %v", err)
+	}
+	if got := doc.Find("p").Text(); got != "primary" {
+		t.Errorf("page = %q, want the primary page", got)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary source called %d times, want 0", secondary.calls)
+	}
+}
+
+func TestFallbackSourceFallsBack(t *testing.T) {
+	primary := &stubSource{name: "primary", err: errors.New("browser crashed")}
+	secondary := &stubSource{name: "secondary", html: "<p>secondary</p>"}
+	src := FallbackSource{Sources: []CourseSource{primary, secondary}}
+
+	doc, err := src.FetchPage(context.Background(), "https://example.com/course/02105")
+	if err != nil {
+		t.Fatalf("FetchPage: %v", err)
+	}
+	if got := doc.Find("p").Text(); got != "secondary" {
+		t.Errorf("page = %q, want the secondary page", got)
+	}
+	if primary.calls != 1 || secondary.calls != 1 {
+		t.Errorf("calls = %d, %d, want 1, 1", primary.calls, secondary.calls)
+	}
+}
+
+func TestFallbackSourceAllFail(t *testing.T) {
+	errPrimary := errors.New("browser crashed")
+	errSecondary := errors.New("connection refused")
+	src := FallbackSource{Sources: []CourseSource{
+		&stubSource{name: "primary", err: errPrimary},
+		&stubSource{name: "secondary", err: errSecondary},
+	}}
+
+	_, err := src.FetchPage(context.Background(), "https://example.com/course/02105")
+	if err == nil {
+		t.Fatal("FetchPage succeeded, want an error")
+	}
+	if !errors.Is(err, errPrimary) || !errors.Is(err, errSecondary) {
+		t.Errorf("error %q does not wrap both source errors", err)
+	}
+	for _, name := range []string{"primary: ", "secondary: "} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name source %q", err, name)
+		}
+	}
+}
+
+func TestFallbackSourceStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	secondary := &stubSource{name: "secondary", html: "<p>secondary</p>"}
+	src := FallbackSource{Sources: []CourseSource{
+		&stubSource{name: "primary", err: context.Canceled},
+		secondary,
+	}}
+
+	if _, err := src.FetchPage(ctx, "https://example.com/course/02105"); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary source called %d times after cancellation, want 0", secondary.calls)
+	}
+}
+
+func TestFallbackSourceName(t *testing.T) {
+	src := FallbackSource{Sources: []CourseSource{&stubSource{name: "chromedp"}, &stubSource{name: "http"}}}
+	if got := src.Name(); got != "chromedp->http" {
+		t.Errorf("Name() = %q, want %q", got, "chromedp->http")
+	}
+}
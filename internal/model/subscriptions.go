@@ -0,0 +1,226 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+)
+
+// Subscription is a user's request to be notified when a course changes.
+type Subscription struct {
+	UserID string `json:"userId"`
+	// ChannelID is the channel to post notifications in. Empty means a direct message.
+	ChannelID string `json:"channelId,omitempty"`
+}
+
+// SubscriptionStore keeps the subscribers of each course, persisted as a JSON file,
+// and a snapshot of each subscribed course to compare new versions against.
+type SubscriptionStore struct {
+	path        string
+	snapshotDir string
+
+	mu sync.Mutex
+	// subscriptions maps course number -> subscriptions.
+	subscriptions map[string][]Subscription
+}
+
+// NewSubscriptionStore loads the store from path. A missing file gives an empty store.
+// Snapshots are kept as one file per course in snapshotDir.
+func NewSubscriptionStore(path, snapshotDir string) (*SubscriptionStore, error) {
+	store := &SubscriptionStore{
+		path:          path,
+		snapshotDir:   snapshotDir,
+		subscriptions: make(map[string][]Subscription),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.subscriptions); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Subscribe subscribes the user to the course. If the user is already subscribed, only the
+// channel is updated and false is returned.
+func (s *SubscriptionStore) Subscribe(courseNumber string, sub Subscription) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.subscriptions[courseNumber]
+	idx := slices.IndexFunc(subs, func(existing Subscription) bool { return existing.UserID == sub.UserID })
+	if idx >= 0 {
+		if subs[idx] == sub {
+			return false, nil
+		}
+		updated := slices.Clone(subs)
+		updated[idx] = sub
+		return false, s.updateLocked(courseNumber, updated)
+	}
+	return true, s.updateLocked(courseNumber, append(slices.Clone(subs), sub))
+}
+
+// Unsubscribe removes the user's subscription to the course.
+// It returns false if the user was not subscribed.
+func (s *SubscriptionStore) Unsubscribe(courseNumber, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.subscriptions[courseNumber]
+	idx := slices.IndexFunc(subs, func(existing Subscription) bool { return existing.UserID == userID })
+	if idx < 0 {
+		return false, nil
+	}
+
+	return true, s.updateLocked(courseNumber, slices.Delete(slices.Clone(subs), idx, idx+1))
+}
+
+// updateLocked replaces the subscriptions to the course and saves the store.
+// If saving fails, the change is undone, so memory never holds subscriptions that are not on disk.
+// s.mu must be held.
+func (s *SubscriptionStore) updateLocked(courseNumber string, subs []Subscription) error {
+	previous := s.subscriptions[courseNumber]
+	s.setLocked(courseNumber, subs)
+	if err := s.saveLocked(); err != nil {
+		s.setLocked(courseNumber, previous)
+		return err
+	}
+	return nil
+}
+
+// setLocked replaces the subscriptions to the course, dropping courses without any. s.mu must be held.
+func (s *SubscriptionStore) setLocked(courseNumber string, subs []Subscription) {
+	if len(subs) == 0 {
+		delete(s.subscriptions, courseNumber)
+		return
+	}
+	s.subscriptions[courseNumber] = subs
+}
+
+// Courses returns the numbers of all courses with at least one subscriber, sorted.
+func (s *SubscriptionStore) Courses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	courses := make([]string, 0, len(s.subscriptions))
+	for courseNumber := range s.subscriptions {
+		courses = append(courses, courseNumber)
+	}
+	sort.Strings(courses)
+	return courses
+}
+
+// Subscribers returns the subscriptions to the course.
+func (s *SubscriptionStore) Subscribers(courseNumber string) []Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.subscriptions[courseNumber])
+}
+
+// UserCourses returns the numbers of the courses the user is subscribed to, sorted.
+func (s *SubscriptionStore) UserCourses(userID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var courses []string
+	for courseNumber, subs := range s.subscriptions {
+		if slices.ContainsFunc(subs, func(sub Subscription) bool { return sub.UserID == userID }) {
+			courses = append(courses, courseNumber)
+		}
+	}
+	sort.Strings(courses)
+	return courses
+}
+
+// Snapshot returns the last seen version of the course, or nil if there is none.
+func (s *SubscriptionStore) Snapshot(courseNumber string) (*Course, error) {
+	data, err := os.ReadFile(s.snapshotPath(courseNumber))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	course := &Course{}
+	if err := json.Unmarshal(data, course); err != nil {
+		return nil, fmt.Errorf("corrupt snapshot of course %s: %w", courseNumber, err)
+	}
+	return course, nil
+}
+
+// SaveSnapshot stores the course as the last seen version.
+func (s *SubscriptionStore) SaveSnapshot(course *Course) error {
+	data, err := json.MarshalIndent(course, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.snapshotPath(course.CourseNumber), data)
+}
+
+// UpdateSnapshot compares the course with its last seen version, stores it as the new snapshot
+// and returns the changes. The first version seen of a course has no changes. If the academic
+// year changed, the changes are labelled with the years, otherwise "Before" and "Now".
+func (s *SubscriptionStore) UpdateSnapshot(course *Course) ([]FieldChange, error) {
+	snapshot, err := s.Snapshot(course.CourseNumber)
+	if err != nil {
+		// The unreadable snapshot is replaced, so the next check has something to compare against.
+		log.Println("Error reading course snapshot:", err)
+	}
+
+	var changes []FieldChange
+	if snapshot != nil {
+		oldLabel, newLabel := "Before", "Now"
+		if snapshot.AcademicYear != course.AcademicYear {
+			oldLabel, newLabel = snapshot.AcademicYear, course.AcademicYear
+		}
+		changes = DiffCourses(snapshot, course, oldLabel, newLabel)
+	}
+	return changes, s.SaveSnapshot(course)
+}
+
+// snapshotPath returns the snapshot file of a course. Course numbers are validated
+// before they reach the store, so they are safe to use as file names.
+func (s *SubscriptionStore) snapshotPath(courseNumber string) string {
+	return filepath.Join(s.snapshotDir, filepath.Base(courseNumber)+".json")
+}
+
+// saveLocked writes the subscriptions to disk. s.mu must be held.
+func (s *SubscriptionStore) saveLocked() error {
+	data, err := json.MarshalIndent(s.subscriptions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+var (
+	subscriptionStoreOnce sync.Once
+	subscriptionStore     *SubscriptionStore
+	subscriptionStoreErr  error
+)
+
+// GetSubscriptionStore returns the store of course subscriptions, loading it from the configured
+// file on first use.
+func GetSubscriptionStore() (*SubscriptionStore, error) {
+	subscriptionStoreOnce.Do(func() {
+		path, snapshotDir := "data/subscriptions.json", "data/snapshots"
+		if config.GlobalConfig != nil {
+			path, snapshotDir = config.GlobalConfig.SubscriptionsFile, config.GlobalConfig.SnapshotsDir
+		}
+		subscriptionStore, subscriptionStoreErr = NewSubscriptionStore(path, snapshotDir)
+	})
+	return subscriptionStore, subscriptionStoreErr
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func newTestSubscriptionStore(t *testing.T) *SubscriptionStore {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewSubscriptionStore(filepath.Join(dir, "subscriptions.json"), filepath.Join(dir, "snapshots"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSubscriptionStoreSubscribe(t *testing.T) {
+	store := newTestSubscriptionStore(t)
+	tests := []struct {
+		name        string
+		course      string
+		sub         Subscription
+		wantAdded   bool
+		wantCourses []string
+	}{
+		{"new subscription", "02105", Subscription{UserID: "ann"}, true, []string{"02105"}},
+		{"same subscription again", "02105", Subscription{UserID: "ann"}, false, []string{"02105"}},
+		{"moved to a channel", "02105", Subscription{UserID: "ann", ChannelID: "chan"}, false, []string{"02105"}},
+		{"second course", "01017", Subscription{UserID: "ann"}, true, []string{"01017", "02105"}},
+	}
+	for _, tt := range tests {
+		added, err := store.Subscribe(tt.course, tt.sub)
+		if err != nil {
+			t.Fatalf("%s: Subscribe: %v", tt.name, err)
+		}
+		if added != tt.wantAdded {
+			t.Errorf("%s: Subscribe = %t, want %t", tt.name, added, tt.wantAdded)
+		}
+		if got := store.UserCourses("ann"); !slices.Equal(got, tt.wantCourses) {
+			t.Errorf("%s: UserCourses = %q, want %q", tt.name, got, tt.wantCourses)
+		}
+	}
+	if got := store.Subscribers("02105"); len(got) != 1 || got[0].ChannelID != "chan" {
+		t.Errorf("Subscribers(02105) = %+v, want ann in channel chan", got)
+	}
+
+	// The subscriptions survive a reload.
+	reloaded, err := NewSubscriptionStore(store.path, store.snapshotDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Courses(); !slices.Equal(got, []string{"01017", "02105"}) {
+		t.Errorf("Courses after reload = %q, want [01017 02105]", got)
+	}
+}
+
+func TestSubscriptionStoreUnsubscribe(t *testing.T) {
+	store := newTestSubscriptionStore(t)
+	store.Subscribe("02105", Subscription{UserID: "ann"})
+	store.Subscribe("02105", Subscription{UserID: "bob"})
+
+	if removed, err := store.Unsubscribe("02105", "carl"); removed || err != nil {
+		t.Errorf("Unsubscribe(carl) = %t, %v, want false, nil", removed, err)
+	}
+	if removed, err := store.Unsubscribe("02105", "ann"); !removed || err != nil {
+		t.Errorf("Unsubscribe(ann) = %t, %v, want true, nil", removed, err)
+	}
+	if got := store.Subscribers("02105"); len(got) != 1 || got[0].UserID != "bob" {
+		t.Errorf("Subscribers after unsubscribing ann = %+v, want only bob", got)
+	}
+	store.Unsubscribe("02105", "bob")
+	if got := store.Courses(); len(got) != 0 {
+		t.Errorf("Courses after the last unsubscribe = %q, want none", got)
+	}
+}
+
+func TestSubscriptionStoreRollsBackFailedSaves(t *testing.T) {
+	store := newTestSubscriptionStore(t)
+	if _, err := store.Subscribe("02105", Subscription{UserID: "ann"}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// Saving below a regular file fails.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store.path = filepath.Join(blocker, "subscriptions.json")
+
+	if _, err := store.Subscribe("02105", Subscription{UserID: "ann", ChannelID: "chan"}); err == nil {
+		t.Error("moving the subscription succeeded although the store could not be saved")
+	}
+	if _, err := store.Subscribe("01017", Subscription{UserID: "ann"}); err == nil {
+		t.Error("Subscribe succeeded although the store could not be saved")
+	}
+	if _, err := store.Unsubscribe("02105", "ann"); err == nil {
+		t.Error("Unsubscribe succeeded although the store could not be saved")
+	}
+	if got := store.Subscribers("02105"); len(got) != 1 || got[0] != (Subscription{UserID: "ann"}) {
+		t.Errorf("Subscribers(02105) = %+v after failed saves, want the saved subscription", got)
+	}
+	if got := store.UserCourses("ann"); !slices.Equal(got, []string{"02105"}) {
+		t.Errorf("UserCourses = %q after failed saves, want [02105]", got)
+	}
+}
+
+func TestSubscriptionStoreUpdateSnapshot(t *testing.T) {
+	store := newTestSubscriptionStore(t)
+	course := &Course{CourseNumber: "02105", Title: "Algorithms 1", ECTS: "5", AcademicYear: "2025-2026"}
+
+	// The first version seen is only recorded.
+	changes, err := store.UpdateSnapshot(course)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("first UpdateSnapshot = %v, %v, want no changes", changes, err)
+	}
+	if snapshot, err := store.Snapshot("02105"); err != nil || snapshot == nil || snapshot.ECTS != "5" {
+		t.Fatalf("Snapshot = %+v, %v, want the recorded course", snapshot, err)
+	}
+
+	// An unchanged course has no changes.
+	if changes, _ := store.UpdateSnapshot(course); len(changes) != 0 {
+		t.Errorf("UpdateSnapshot of an unchanged course = %+v, want none", changes)
+	}
+
+	changed := *course
+	changed.ECTS = "7.5"
+	changes, err = store.UpdateSnapshot(&changed)
+	if err != nil {
+		t.Fatalf("UpdateSnapshot: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != "ECTS" || changes[0].Old != "5" || changes[0].New != "7.5" ||
+		changes[0].OldLabel != "Before" || changes[0].NewLabel != "Now" {
+		t.Errorf("changes = %+v, want ECTS 5 -> 7.5 labelled Before and Now", changes)
+	}
+
+	// A new academic year labels the changes with the years.
+	nextYear := changed
+	nextYear.AcademicYear, nextYear.Title = "2026-2027", "Algorithms and Data Structures 1"
+	changes, _ = store.UpdateSnapshot(&nextYear)
+	if len(changes) != 1 || changes[0].OldLabel != "2025-2026" || changes[0].NewLabel != "2026-2027" {
+		t.Errorf("changes = %+v, want the title change labelled with the academic years", changes)
+	}
+}
+
+func TestSubscriptionStoreReplacesCorruptSnapshot(t *testing.T) {
+	store := newTestSubscriptionStore(t)
+	if err := os.MkdirAll(store.snapshotDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.snapshotPath("02105"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	course := &Course{CourseNumber: "02105", Title: "Algorithms 1"}
+	if changes, err := store.UpdateSnapshot(course); err != nil || len(changes) != 0 {
+		t.Errorf("UpdateSnapshot = %v, %v, want no changes and no error", changes, err)
+	}
+	if snapshot, err := store.Snapshot("02105"); err != nil || snapshot == nil {
+		t.Errorf("Snapshot = %v, %v, want the corrupt snapshot replaced", snapshot, err)
+	}
+}
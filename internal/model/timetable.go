@@ -0,0 +1,64 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// TimetableEntry is one course taught in one weekly slot.
+type TimetableEntry struct {
+	Course *Course
+	Slot   ScheduleSlot
+}
+
+// Timetable is the weekly overview of a set of courses in one semester.
+type Timetable struct {
+	Semester SemesterKey
+	// Days holds the 13-week slots per weekday, sorted by start time.
+	Days map[time.Weekday][]TimetableEntry
+	// ThreeWeek holds the courses of each 3-week period, e.g. "January".
+	ThreeWeek map[string][]*Course
+	// Unscheduled are the courses whose schedule could not be interpreted.
+	Unscheduled []*Course
+}
+
+// BuildTimetable places the courses in a weekly timetable for the given semester.
+// Slots from the other semester are left out; courses without any slots are listed as unscheduled.
+func BuildTimetable(courses []*Course, semester SemesterKey) Timetable {
+	timetable := Timetable{
+		Semester:  semester,
+		Days:      make(map[time.Weekday][]TimetableEntry),
+		ThreeWeek: make(map[string][]*Course),
+	}
+
+	for _, course := range courses {
+		slots := course.CourseScheduleSection.ParsedSchedule().Slots
+		if len(slots) == 0 {
+			timetable.Unscheduled = append(timetable.Unscheduled, course)
+			continue
+		}
+
+		threeWeekSeen := make(map[string]bool)
+		for _, slot := range slots {
+			// Slots without a known semester are shown in every semester.
+			if slot.Semester != "" && slot.Semester != semester.Semester {
+				continue
+			}
+			if slot.Period == Period3Week {
+				if !threeWeekSeen[slot.Block] {
+					threeWeekSeen[slot.Block] = true
+					timetable.ThreeWeek[slot.Block] = append(timetable.ThreeWeek[slot.Block], course)
+				}
+				continue
+			}
+			timetable.Days[slot.Weekday] = append(timetable.Days[slot.Weekday], TimetableEntry{Course: course, Slot: slot})
+		}
+	}
+
+	for _, entries := range timetable.Days {
+		sort.SliceStable(entries, func(a, b int) bool {
+			return entries[a].Slot.Start < entries[b].Slot.Start
+		})
+	}
+	return timetable
+}
@@ -0,0 +1,207 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Layout of the timetable image, in pixels.
+const (
+	ttTimeColumnWidth = 50
+	ttDayColumnWidth  = 170
+	ttHourHeight      = 48
+	ttTitleHeight     = 28
+	ttHeaderHeight    = 22
+	ttPadding         = 10
+	ttLineHeight      = 16
+)
+
+var (
+	ttBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	ttGridColor  = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	ttTextColor  = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	ttHeaderFill = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	// ttPalette colors course blocks; each course keeps its color across the week.
+	ttPalette = []color.RGBA{
+		{0x99, 0x00, 0x00, 0xff}, // DTU red
+		{0x2f, 0x3e, 0xea, 0xff},
+		{0x00, 0x8a, 0x5c, 0xff},
+		{0xc2, 0x8e, 0x00, 0xff},
+		{0xfc, 0x76, 0x34, 0xff},
+		{0x79, 0x23, 0x8e, 0xff},
+		{0x00, 0x88, 0x35, 0xff},
+		{0xe8, 0x3f, 0x48, 0xff},
+	}
+)
+
+// RenderTimetablePNG draws the 13-week slots of the timetable as a week grid, with the
+// 3-week periods listed below it, and returns the PNG encoded image.
+func RenderTimetablePNG(timetable Timetable, title string) ([]byte, error) {
+	firstHour, lastHour := timetableHours(timetable)
+
+	var threeWeekLines []string
+	for _, period := range ThreeWeekPeriods {
+		courses := timetable.ThreeWeek[period]
+		if len(courses) == 0 {
+			continue
+		}
+		line := period + " (3 weeks):"
+		for _, course := range courses {
+			line += " " + course.CourseNumber
+		}
+		threeWeekLines = append(threeWeekLines, line)
+	}
+
+	gridTop := ttPadding + ttTitleHeight + ttHeaderHeight
+	gridHeight := (lastHour - firstHour) * ttHourHeight
+	width := 2*ttPadding + ttTimeColumnWidth + 5*ttDayColumnWidth
+	height := gridTop + gridHeight + ttPadding + len(threeWeekLines)*ttLineHeight + ttPadding
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(ttBackground), image.Point{}, draw.Src)
+
+	drawText(img, title, ttPadding, ttPadding+18, ttTextColor)
+
+	// Day headers and the vertical grid lines.
+	gridLeft := ttPadding + ttTimeColumnWidth
+	for day := 0; day < 5; day++ {
+		x := gridLeft + day*ttDayColumnWidth
+		fillRect(img, image.Rect(x, gridTop-ttHeaderHeight, x+ttDayColumnWidth, gridTop), ttHeaderFill)
+		drawText(img, (time.Monday + time.Weekday(day)).String(), x+6, gridTop-6, ttBackground)
+		fillRect(img, image.Rect(x, gridTop, x+1, gridTop+gridHeight), ttGridColor)
+	}
+	fillRect(img, image.Rect(gridLeft+5*ttDayColumnWidth, gridTop, gridLeft+5*ttDayColumnWidth+1, gridTop+gridHeight), ttGridColor)
+
+	// Hour labels and the horizontal grid lines.
+	for hour := firstHour; hour <= lastHour; hour++ {
+		y := gridTop + (hour-firstHour)*ttHourHeight
+		fillRect(img, image.Rect(gridLeft, y, gridLeft+5*ttDayColumnWidth, y+1), ttGridColor)
+		if hour < lastHour {
+			drawText(img, fmt.Sprintf("%02d:00", hour), ttPadding, y+13, ttTextColor)
+		}
+	}
+
+	colors := courseColors(timetable)
+	for day := 0; day < 5; day++ {
+		entries := timetable.Days[time.Monday+time.Weekday(day)]
+		lanes, laneCount := assignLanes(entries)
+		laneWidth := (ttDayColumnWidth - 4) / laneCount
+
+		for idx, entry := range entries {
+			x0 := gridLeft + day*ttDayColumnWidth + 2 + lanes[idx]*laneWidth
+			y0 := gridTop + int((entry.Slot.Start-time.Duration(firstHour)*time.Hour)*time.Duration(ttHourHeight)/time.Hour)
+			y1 := gridTop + int((entry.Slot.End-time.Duration(firstHour)*time.Hour)*time.Duration(ttHourHeight)/time.Hour)
+			rect := image.Rect(x0+1, y0+1, x0+laneWidth-1, y1-1)
+			fillRect(img, rect, colors[entry.Course.CourseNumber])
+
+			maxChars := (rect.Dx() - 8) / basicfont.Face7x13.Advance
+			lines := []string{entry.Course.CourseNumber + " " + entry.Slot.Block, entry.Course.Title}
+			for line, text := range lines {
+				y := rect.Min.Y + 14 + line*ttLineHeight
+				if y > rect.Max.Y-2 {
+					break
+				}
+				drawText(img, clip(text, maxChars), rect.Min.X+4, y, ttBackground)
+			}
+		}
+	}
+
+	for idx, line := range threeWeekLines {
+		drawText(img, line, ttPadding, gridTop+gridHeight+ttPadding+(idx+1)*ttLineHeight-4, ttTextColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// timetableHours returns the whole hours the grid should span: 8-17 unless slots fall outside.
+func timetableHours(timetable Timetable) (first, last int) {
+	first, last = 8, 17
+	for _, entries := range timetable.Days {
+		for _, entry := range entries {
+			if h := int(entry.Slot.Start / time.Hour); h < first {
+				first = h
+			}
+			if h := int((entry.Slot.End + time.Hour - 1) / time.Hour); h > last {
+				last = h
+			}
+		}
+	}
+	return first, last
+}
+
+// courseColors gives every course in the timetable a color from the palette.
+func courseColors(timetable Timetable) map[string]color.RGBA {
+	colors := make(map[string]color.RGBA)
+	for day := time.Monday; day <= time.Friday; day++ {
+		for _, entry := range timetable.Days[day] {
+			if _, ok := colors[entry.Course.CourseNumber]; !ok {
+				colors[entry.Course.CourseNumber] = ttPalette[len(colors)%len(ttPalette)]
+			}
+		}
+	}
+	return colors
+}
+
+// assignLanes places overlapping entries of a day side by side.
+// The entries must be sorted by start time, which BuildTimetable guarantees.
+func assignLanes(entries []TimetableEntry) (lanes []int, laneCount int) {
+	lanes = make([]int, len(entries))
+	var laneEnds []time.Duration
+	for idx, entry := range entries {
+		lane := -1
+		for l, end := range laneEnds {
+			if end <= entry.Slot.Start {
+				lane = l
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, 0)
+		}
+		laneEnds[lane] = entry.Slot.End
+		lanes[idx] = lane
+	}
+	return lanes, max(1, len(laneEnds))
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawText draws text with its baseline at y.
+func drawText(img *image.RGBA, text string, x, y int, c color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// clip shortens text to at most maxChars characters, ending it with "." if anything was cut.
+func clip(text string, maxChars int) string {
+	runes := []rune(text)
+	if maxChars <= 0 {
+		return ""
+	}
+	if len(runes) <= maxChars {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:maxChars-1])) + "."
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
+)
+
+// ScheduleStore keeps each user's list of courses per semester, persisted as a JSON file.
+type ScheduleStore struct {
+	path string
+
+	mu sync.Mutex
+	// schedules maps user ID -> semester (e.g. "Autumn 2025") -> course numbers.
+	schedules map[string]map[string][]string
+}
+
+// NewScheduleStore loads the store from path. A missing file gives an empty store.
+func NewScheduleStore(path string) (*ScheduleStore, error) {
+	store := &ScheduleStore{
+		path:      path,
+		schedules: make(map[string]map[string][]string),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.schedules); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Add adds the course to the user's schedule for the semester.
+// It returns false if the course was already there.
+func (s *ScheduleStore) Add(userID string, semester SemesterKey, courseNumber string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	courses := s.schedules[userID][semester.String()]
+	if slices.Contains(courses, courseNumber) {
+		return false, nil
+	}
+	return true, s.updateLocked(userID, semester, append(slices.Clone(courses), courseNumber))
+}
+
+// Remove removes the course from the user's schedule for the semester.
+// It returns false if the course was not there.
+func (s *ScheduleStore) Remove(userID string, semester SemesterKey, courseNumber string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	courses := s.schedules[userID][semester.String()]
+	idx := slices.Index(courses, courseNumber)
+	if idx < 0 {
+		return false, nil
+	}
+	return true, s.updateLocked(userID, semester, slices.Delete(slices.Clone(courses), idx, idx+1))
+}
+
+// updateLocked replaces the user's courses for the semester and saves the store.
+// If saving fails, the change is undone, so memory never holds a schedule that is not on disk.
+// s.mu must be held.
+func (s *ScheduleStore) updateLocked(userID string, semester SemesterKey, courses []string) error {
+	previous := s.schedules[userID][semester.String()]
+	s.setLocked(userID, semester, courses)
+	if err := s.saveLocked(); err != nil {
+		s.setLocked(userID, semester, previous)
+		return err
+	}
+	return nil
+}
+
+// setLocked replaces the user's courses for the semester, dropping empty schedules. s.mu must be held.
+func (s *ScheduleStore) setLocked(userID string, semester SemesterKey, courses []string) {
+	if len(courses) == 0 {
+		delete(s.schedules[userID], semester.String())
+		if len(s.schedules[userID]) == 0 {
+			delete(s.schedules, userID)
+		}
+		return
+	}
+	if s.schedules[userID] == nil {
+		s.schedules[userID] = make(map[string][]string)
+	}
+	s.schedules[userID][semester.String()] = courses
+}
+
+// Courses returns the course numbers in the user's schedule for the semester.
+func (s *ScheduleStore) Courses(userID string, semester SemesterKey) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.schedules[userID][semester.String()])
+}
+
+// saveLocked writes the store to disk. s.mu must be held.
+func (s *ScheduleStore) saveLocked() error {
+	data, err := json.MarshalIndent(s.schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+var (
+	scheduleStoreOnce sync.Once
+	scheduleStore     *ScheduleStore
+	scheduleStoreErr  error
+)
+
+// GetScheduleStore returns the store of personal schedules, loading it from the configured file on first use.
+func GetScheduleStore() (*ScheduleStore, error) {
+	scheduleStoreOnce.Do(func() {
+		path := "data/schedules.json"
+		if config.GlobalConfig != nil {
+			path = config.GlobalConfig.SchedulesFile
+		}
+		scheduleStore, scheduleStoreErr = NewScheduleStore(path)
+	})
+	return scheduleStore, scheduleStoreErr
+}
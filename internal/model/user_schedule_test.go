@@ -0,0 +1,58 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestScheduleStoreRollsBackFailedSaves(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewScheduleStore(filepath.Join(dir, "schedules.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	semester := SemesterKey{Semester: SemesterAutumn, Year: 2026}
+	if _, err := store.Add("user", semester, "02105"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// Saving below a regular file fails.
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store.path = filepath.Join(blocker, "schedules.json")
+
+	if _, err := store.Remove("user", semester, "02105"); err == nil {
+		t.Fatal("Remove succeeded although the store could not be saved")
+	}
+	if _, err := store.Add("user", semester, "01017"); err == nil {
+		t.Fatal("Add succeeded although the store could not be saved")
+	}
+	if got := store.Courses("user", semester); !slices.Equal(got, []string{"02105"}) {
+		t.Errorf("Courses() = %q after failed saves, want the saved schedule [02105]", got)
+	}
+}
+
+func TestScheduleStoreRemoveLastCourse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	store, err := NewScheduleStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	semester := SemesterKey{Semester: SemesterSpring, Year: 2027}
+	store.Add("user", semester, "02105")
+	if removed, err := store.Remove("user", semester, "02105"); !removed || err != nil {
+		t.Fatalf("Remove = %v, %v, want true, nil", removed, err)
+	}
+
+	reloaded, err := NewScheduleStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.schedules) != 0 {
+		t.Errorf("schedules = %v after removing the last course, want none", reloaded.schedules)
+	}
+}
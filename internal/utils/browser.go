@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrBrowserPoolClosed is returned when fetching through a pool that has been shut down.
+var ErrBrowserPoolClosed = errors.New("browser pool is closed")
+
+// browserHealthInterval is how often the pool checks that the browser still responds.
+const browserHealthInterval = 30 * time.Second
+
+// BrowserOptions controls which browser the pool uses.
+type BrowserOptions struct {
+	// RemoteURL is the DevTools URL of an existing Chrome, e.g. "ws://chrome:9222".
+	// If set, the pool connects to it instead of launching a local browser.
+	RemoteURL string
+	// ExecPath is the Chrome binary to launch. Empty uses chromedp's default lookup.
+	ExecPath string
+	// Flags are extra command line flags, either "name" or "name=value".
+	Flags []string
+	// UserAgent overrides the user-agent of every tab when set.
+	UserAgent string
+}
+
+// newAllocator returns the chromedp allocator described by the options.
+func (o BrowserOptions) newAllocator() (context.Context, context.CancelFunc) {
+	if o.RemoteURL != "" {
+		return chromedp.NewRemoteAllocator(context.Background(), o.RemoteURL)
+	}
+
+	opts := append([]chromedp.ExecAllocatorOption(nil), chromedp.DefaultExecAllocatorOptions[:]...)
+	if o.ExecPath != "" {
+		opts = append(opts, chromedp.ExecPath(o.ExecPath))
+	}
+	for _, flag := range o.Flags {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(flag, "-"), "=")
+		switch {
+		case !hasValue:
+			opts = append(opts, chromedp.Flag(name, true))
+		case value == "false":
+			opts = append(opts, chromedp.Flag(name, false))
+		default:
+			opts = append(opts, chromedp.Flag(name, value))
+		}
+	}
+	if o.UserAgent != "" {
+		opts = append(opts, chromedp.UserAgent(o.UserAgent))
+	}
+	return chromedp.NewExecAllocator(context.Background(), opts...)
+}
+
+// browserTab is a reusable browser tab. The generation ties it to the browser it was opened in,
+// so tabs from a crashed browser are never handed out again.
+type browserTab struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	generation int
+}
+
+// BrowserPool keeps a single long-lived headless browser and a bounded number of tabs in it.
+// The browser is started lazily on the first fetch and restarted if a health check fails.
+type BrowserPool struct {
+	mu            sync.Mutex
+	maxTabs       int
+	options       BrowserOptions
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	generation    int
+	idle          []*browserTab
+	closed        bool
+
+	sem      chan struct{} // limits how many pages are rendered at once
+	stopChan chan struct{} // channel to signal the health check goroutine to stop
+	stopOnce sync.Once
+}
+
+// NewBrowserPool creates a pool that renders at most maxTabs pages concurrently.
+func NewBrowserPool(maxTabs int, options BrowserOptions) *BrowserPool {
+	if maxTabs < 1 {
+		maxTabs = 1
+	}
+	pool := &BrowserPool{
+		maxTabs:  maxTabs,
+		options:  options,
+		sem:      make(chan struct{}, maxTabs),
+		stopChan: make(chan struct{}),
+	}
+	// Start a background goroutine to periodically check the browser.
+	go pool.healthLoop()
+	return pool
+}
+
+// FetchPage renders the given course URL in one of the pool's tabs.
+// It blocks while all tabs are busy, or until ctx is done.
+func (p *BrowserPool) FetchPage(ctx context.Context, url string) (*goquery.Document, error) {
+	select {
+	case p.sem <- struct{}{}:
+		defer func() { <-p.sem }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-p.stopChan:
+		return nil, ErrBrowserPoolClosed
+	}
+
+	tab, err := p.acquireTab()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the render by both our own timeout and the caller's context,
+	// without tying the lifetime of the tab itself to either of them.
+	runCtx, cancel := context.WithTimeout(tab.ctx, dynamicPageTimeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	doc, err := renderCoursePage(runCtx, url)
+	if err != nil {
+		// The tab may be stuck mid-navigation, so we close it rather than reuse it.
+		tab.cancel()
+		return nil, err
+	}
+
+	p.releaseTab(tab)
+	return doc, nil
+}
+
+// acquireTab returns an idle tab from the current browser, or opens a new one.
+func (p *BrowserPool) acquireTab() (*browserTab, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrBrowserPoolClosed
+	}
+
+	for len(p.idle) > 0 {
+		tab := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if tab.generation == p.generation && tab.ctx.Err() == nil {
+			return tab, nil
+		}
+		tab.cancel()
+	}
+
+	if err := p.startBrowserLocked(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := chromedp.NewContext(p.browserCtx)
+	// A remote browser was started without our flags, so the user-agent is set per tab instead.
+	if p.options.RemoteURL != "" && p.options.UserAgent != "" {
+		if err := chromedp.Run(ctx, emulation.SetUserAgentOverride(p.options.UserAgent)); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	return &browserTab{ctx: ctx, cancel: cancel, generation: p.generation}, nil
+}
+
+// releaseTab puts a healthy tab back for reuse.
+func (p *BrowserPool) releaseTab(tab *browserTab) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || tab.generation != p.generation || len(p.idle) >= p.maxTabs {
+		tab.cancel()
+		return
+	}
+	p.idle = append(p.idle, tab)
+}
+
+// startBrowserLocked launches the browser if it is not already running. p.mu must be held.
+func (p *BrowserPool) startBrowserLocked() error {
+	if p.browserCtx != nil && p.browserCtx.Err() == nil {
+		return nil
+	}
+	p.stopBrowserLocked()
+
+	allocCtx, allocCancel := p.options.newAllocator()
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+
+	// Running without actions starts the browser and opens its first tab.
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return err
+	}
+
+	p.allocCancel = allocCancel
+	p.browserCtx = browserCtx
+	p.browserCancel = browserCancel
+	p.generation++
+	if p.options.RemoteURL != "" {
+		log.Printf("[Browser pool] connected to remote browser at %s (generation %d)", p.options.RemoteURL, p.generation)
+	} else {
+		log.Printf("[Browser pool] started browser (generation %d)", p.generation)
+	}
+	return nil
+}
+
+// stopBrowserLocked closes the browser and all of its tabs. p.mu must be held.
+func (p *BrowserPool) stopBrowserLocked() {
+	for _, tab := range p.idle {
+		tab.cancel()
+	}
+	p.idle = nil
+
+	if p.browserCancel != nil {
+		p.browserCancel()
+		p.browserCancel = nil
+	}
+	if p.allocCancel != nil {
+		p.allocCancel()
+		p.allocCancel = nil
+	}
+	p.browserCtx = nil
+}
+
+// healthLoop periodically checks that the browser still responds, and restarts it if not.
+func (p *BrowserPool) healthLoop() {
+	ticker := time.NewTicker(browserHealthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkHealth()
+		case <-p.stopChan:
+			// Pool closed; exit the health loop.
+			return
+		}
+	}
+}
+
+// checkHealth evaluates a trivial expression in the browser's first tab.
+// If that fails, the browser is shut down and relaunched on the next fetch.
+func (p *BrowserPool) checkHealth() {
+	p.mu.Lock()
+	browserCtx := p.browserCtx
+	p.mu.Unlock()
+	if browserCtx == nil {
+		// Not started yet, nothing to check.
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(browserCtx, 5*time.Second)
+	defer cancel()
+
+	var result int
+	err := chromedp.Run(ctx, chromedp.Evaluate(`1 + 1`, &result))
+	if err == nil && result == 2 {
+		return
+	}
+
+	log.Printf("[Browser pool] health check failed, restarting browser: %v", err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Only restart if nobody else has done so in the meantime.
+	if p.browserCtx == browserCtx {
+		p.stopBrowserLocked()
+	}
+}
+
+// Close shuts down the browser. Fetches after Close return ErrBrowserPoolClosed.
+func (p *BrowserPool) Close() {
+	p.stopOnce.Do(func() {
+		close(p.stopChan)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.closed = true
+		p.stopBrowserLocked()
+	})
+}
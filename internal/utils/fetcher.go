@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -10,23 +11,30 @@ import (
 	"github.com/chromedp/chromedp"
 )
 
+// dynamicPageTimeout is how long we wait for a course page to render before giving up.
+const dynamicPageTimeout = 5 * time.Second
+
 // FetchDynamicCoursePage navigates to the given DTU course URL,
 // waits for the dynamic content to load, and returns a goquery Document.
-func FetchDynamicCoursePage(url string) (*goquery.Document, error) {
-	// Create a base Chromedp context.
-	baseCtx := context.Background()
+// It launches a browser just for this request; use a BrowserPool for repeated fetches.
+func FetchDynamicCoursePage(baseCtx context.Context, url string) (*goquery.Document, error) {
 	// Create a new Chromedp context.
 	ctx, cancel := chromedp.NewContext(baseCtx)
 	defer cancel()
 
 	// Set a timeout; if the page doesn't load within this duration, cancel the operation.
-	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 5*time.Second)
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, dynamicPageTimeout)
 	defer cancelTimeout()
 
+	return renderCoursePage(timeoutCtx, url)
+}
+
+// renderCoursePage runs the browser automation steps for a course page in the given chromedp context.
+func renderCoursePage(ctx context.Context, url string) (*goquery.Document, error) {
 	var htmlContent string
 
 	// Run the browser automation steps in the timeout context.
-	err := chromedp.Run(timeoutCtx,
+	err := chromedp.Run(ctx,
 		// Navigate to the page.
 		chromedp.Navigate(url),
 		// Disable automatic reload by overriding setTimeout.
@@ -41,12 +49,41 @@ func FetchDynamicCoursePage(url string) (*goquery.Document, error) {
 	}
 
 	// Create a goquery document from the fully rendered HTML string.
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	return goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+}
+
+// HTTPStatusError is returned by FetchStaticCoursePage for non-200 responses.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status fetching %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// staticFetchClient is shared between static fetches so connections are reused.
+var staticFetchClient = &http.Client{Timeout: 10 * time.Second}
+
+// FetchStaticCoursePage fetches the given DTU course URL with a plain HTTP GET
+// and parses the server-rendered HTML without running any JavaScript.
+func FetchStaticCoursePage(ctx context.Context, url string) (*goquery.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := staticFetchClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPStatusError{URL: url, StatusCode: resp.StatusCode}
+	}
 
-	return doc, nil
+	return goquery.NewDocumentFromReader(resp.Body)
 }
 
 // ExtractText extracts the plain text from the specified selector.
@@ -65,6 +102,22 @@ func ExtractText(doc *goquery.Document, selector string, fieldName string, adjac
 		return labelText == fieldName
 	})
 
+	plainText := cellMarkdown(sel, fieldName)
+
+	var links []string
+	if len(adjacentSelector) > 0 {
+		links = labelLinks(doc.Find(adjacentSelector[0]))
+	}
+
+	return appendLabelLinks(plainText, links)
+}
+
+// homePageLabels are the English and Danish labels of the home page row. Its link is shown
+// as the label, since the link text is usually the full URL.
+var homePageLabels = map[string]bool{"Home page": true, "Hjemmeside": true}
+
+// cellMarkdown converts the contents of a table cell into Markdown, keeping links clickable.
+func cellMarkdown(sel *goquery.Selection, fieldName string) string {
 	var sb strings.Builder
 	sel.Contents().Each(func(i int, node *goquery.Selection) {
 		switch goquery.NodeName(node) {
@@ -77,10 +130,8 @@ func ExtractText(doc *goquery.Document, selector string, fieldName string, adjac
 				if strings.HasPrefix(href, "mailto:") {
 					sb.WriteString(fmt.Sprintf("(%s)", href))
 				} else {
-					if fieldName == "Home page" {
-						text = "Home page"
-						sb.WriteString(fmt.Sprintf("[%s](%s)", text, href))
-						break
+					if label := NormalizeLabel(fieldName); homePageLabels[label] {
+						text = label
 					}
 					sb.WriteString(fmt.Sprintf("[%s](%s)", text, href))
 				}
@@ -92,41 +143,39 @@ func ExtractText(doc *goquery.Document, selector string, fieldName string, adjac
 		}
 	})
 
-	plainText := strings.TrimSpace(sb.String())
+	return strings.TrimSpace(sb.String())
+}
 
+// labelLinks returns the anchors of a label cell as Markdown links.
+func labelLinks(sel *goquery.Selection) []string {
 	var links []string
-	if len(adjacentSelector) > 0 {
-		adjSel := doc.Find(adjacentSelector[0])
-		adjSel.Find("a").Each(func(i int, a *goquery.Selection) {
-			href, exists := a.Attr("href")
-			aText := strings.TrimSpace(a.Text())
-			if exists && href != "" && aText != "" {
-				links = append(links, fmt.Sprintf("([Link to %s](%s))", aText, href))
-			}
-		})
-	}
+	sel.Find("a").Each(func(i int, a *goquery.Selection) {
+		href, exists := a.Attr("href")
+		aText := strings.TrimSpace(a.Text())
+		if exists && href != "" && aText != "" {
+			links = append(links, fmt.Sprintf("([Link to %s](%s))", aText, href))
+		}
+	})
+	return links
+}
 
+// appendLabelLinks appends the comma-separated label links to the text.
+func appendLabelLinks(text string, links []string) string {
 	if len(links) > 0 {
 		joined := strings.Join(links, ", ")
-		if plainText == "" {
+		if text == "" {
 			return joined
 		}
-		return fmt.Sprintf("%s %s", plainText, joined)
+		return fmt.Sprintf("%s %s", text, joined)
 	}
-	return plainText
+	return text
 }
 
 // ExtractFieldByName finds the table row whose label exactly equals fieldName,
 // then extracts Markdown-formatted text from the second cell.
+// Prefer ParseInfoTable when reading several fields from the same document.
 func ExtractFieldByName(doc *goquery.Document, fieldName string) string {
-	// First, try to find the td with a label that has an <a>
-	labelSelector := fmt.Sprintf("td:has(label a:contains('%s'))", fieldName)
-	if doc.Find(labelSelector).Length() == 0 {
-		// Fallback: use the label without the anchor.
-		labelSelector = fmt.Sprintf("td:has(label:contains('%s'))", fieldName)
-	}
-	valueSelector := labelSelector + " + td"
-	return ExtractText(doc, valueSelector, fieldName, labelSelector)
+	return ParseInfoTable(doc).Value(fieldName)
 }
 
 // CourseTypeBlock holds one “top-level” item plus its hidden expansions.
@@ -160,12 +209,10 @@ func (ctb CourseTypeBlock) SetInLine(isInline bool) {
 	panic("CourseTypeBlock does not support inline formatting")
 }
 
-func ExtractCourseTypeAdvanced(doc *goquery.Document) []CourseTypeBlock {
+// ExtractCourseTypeAdvanced parses the value cell of the "Course type" row into blocks.
+func ExtractCourseTypeAdvanced(cell *goquery.Selection) []CourseTypeBlock {
 	var results []CourseTypeBlock
 
-	// Find the <td> for "Course type"
-	cell := doc.Find("td:has(label:contains('Course type')) + td")
-
 	// The top-level <div> that is not #studiebox, e.g. <div>BSc</div>
 	cell.Find("div").Each(func(i int, s *goquery.Selection) {
 		id, _ := s.Attr("id")
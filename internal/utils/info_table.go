@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FieldValue is the value of a single row in the course information table.
+type FieldValue struct {
+	// Label is the normalized label of the row, e.g. "Department involved".
+	Label string
+	// Text is the Markdown-formatted value, including any links found in the label cell.
+	Text string
+	// Cell is the raw value cell, for fields that need custom parsing.
+	Cell *goquery.Selection
+}
+
+// InfoTable holds the rows of the course information table keyed by their exact label.
+// Labels are kept in page order, so iterating with Labels is deterministic.
+type InfoTable struct {
+	labels []string
+	fields map[string]FieldValue
+}
+
+// ParseInfoTable walks every labelled table row of the document once.
+// Unlike a :contains selector, labels are matched exactly, so "Department"
+// never picks up the "Department involved" row.
+func ParseInfoTable(doc *goquery.Document) *InfoTable {
+	table := &InfoTable{
+		fields: make(map[string]FieldValue),
+	}
+
+	doc.Find("tr").Each(func(i int, row *goquery.Selection) {
+		labelCell := row.ChildrenFiltered("td").First()
+		valueCell := labelCell.Next()
+		if labelCell.Length() == 0 || valueCell.Length() == 0 || labelCell.Find("label").Length() == 0 {
+			return
+		}
+
+		// Try getting the text from a nested <label><a>, but if absent, fallback to <label>
+		label := NormalizeLabel(labelCell.Find("label a").Text())
+		if label == "" {
+			label = NormalizeLabel(labelCell.Find("label").Text())
+		}
+		if label == "" {
+			return
+		}
+		// The first row with a given label wins.
+		if _, exists := table.fields[label]; exists {
+			return
+		}
+
+		table.labels = append(table.labels, label)
+		table.fields[label] = FieldValue{
+			Label: label,
+			Text:  appendLabelLinks(cellMarkdown(valueCell, label), labelLinks(labelCell)),
+			Cell:  valueCell,
+		}
+	})
+
+	return table
+}
+
+// NormalizeLabel trims a label, collapses inner whitespace and drops a trailing colon.
+func NormalizeLabel(label string) string {
+	label = strings.Join(strings.Fields(label), " ")
+	return strings.TrimSpace(strings.TrimSuffix(label, ":"))
+}
+
+// Get returns the first field matching one of the given labels,
+// e.g. the English label followed by the Danish one.
+func (t *InfoTable) Get(labels ...string) (FieldValue, bool) {
+	for _, label := range labels {
+		if field, ok := t.fields[NormalizeLabel(label)]; ok {
+			return field, true
+		}
+	}
+	return FieldValue{}, false
+}
+
+// Value returns the Markdown text of the first field matching one of the labels,
+// or an empty string if none of them are present.
+func (t *InfoTable) Value(labels ...string) string {
+	field, _ := t.Get(labels...)
+	return field.Text
+}
+
+// Labels returns the labels of the table in page order.
+func (t *InfoTable) Labels() []string {
+	return append([]string(nil), t.labels...)
+}
+
+// Fields returns a copy of the label to value map.
+func (t *InfoTable) Fields() map[string]FieldValue {
+	fields := make(map[string]FieldValue, len(t.fields))
+	for label, field := range t.fields {
+		fields[label] = field
+	}
+	return fields
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func parseTable(t *testing.T, html string) *InfoTable {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ParseInfoTable(doc)
+}
+
+const englishTable = `<div class="box information"><table>
+<tr><td><label>Danish title</label></td><td>Algoritmer og datastrukturer 1</td></tr>
+<tr><td><label> Language of
+	instruction: </label></td><td>English</td></tr>
+<tr><td><label>Department</label></td><td><a href="http://www.compute.dtu.dk">01 Department of Applied Mathematics and Computer Science</a></td></tr>
+<tr><td><label>Department involved</label></td><td>10 Department of Physics</td></tr>
+<tr><td><label>Responsible</label></td><td>Inge Li Gørtz , <a href="mailto:inge@dtu.dk">inge@dtu.dk</a></td></tr>
+<tr><td><label><a href="https://www.dtu.dk/scheme">Schedule</a></label></td><td>Spring E3A</td></tr>
+<tr><td><label>Home page</label></td><td><a href="https://www2.compute.dtu.dk/courses/02105">https://www2.compute.dtu.dk/courses/02105</a></td></tr>
+<tr><td><label>Schedule</label></td><td>A second schedule row</td></tr>
+<tr><td>Row without a label</td><td>ignored</td></tr>
+</table></div>`
+
+const danishTable = `<div class="box information"><table>
+<tr><td><label>Engelsk titel</label></td><td>Algorithms and Data Structures 1</td></tr>
+<tr><td><label>Undervisningssprog:</label></td><td>Engelsk</td></tr>
+<tr><td><label>Institut</label></td><td>01 Institut for Matematik og Computer Science</td></tr>
+<tr><td><label>Deltagende institut</label></td><td>10 Institut for Fysik</td></tr>
+<tr><td><label>Hjælpemidler</label></td><td>Alle hjælpemidler</td></tr>
+<tr><td><label>Hjemmeside</label></td><td><a href="https://www2.compute.dtu.dk/courses/02105">https://www2.compute.dtu.dk/courses/02105</a></td></tr>
+</table></div>`
+
+func TestParseInfoTableEnglish(t *testing.T) {
+	table := parseTable(t, englishTable)
+
+	for _, tc := range []struct {
+		label, want string
+	}{
+		{"Danish title", "Algoritmer og datastrukturer 1"},
+		// Labels are normalized: whitespace collapsed and the trailing colon dropped.
+		{"Language of instruction", "English"},
+		{"Department", "[01 Department of Applied Mathematics and Computer Science](http://www.compute.dtu.dk)"},
+		{"Department involved", "10 Department of Physics"},
+		{"Responsible", "Inge Li Gørtz , (mailto:inge@dtu.dk)"},
+		// Links in the label cell are appended to the value, and the first row with a label wins.
+		{"Schedule", "Spring E3A ([Link to Schedule](https://www.dtu.dk/scheme))"},
+		{"Home page", "[Home page](https://www2.compute.dtu.dk/courses/02105)"},
+		{"Missing label", ""},
+	} {
+		if got := table.Value(tc.label); got != tc.want {
+			t.Errorf("Value(%q) = %q, want %q", tc.label, got, tc.want)
+		}
+	}
+
+	wantLabels := []string{"Danish title", "Language of instruction", "Department", "Department involved", "Responsible", "Schedule", "Home page"}
+	if got := table.Labels(); !slices.Equal(got, wantLabels) {
+		t.Errorf("Labels() = %q, want %q", got, wantLabels)
+	}
+}
+
+func TestParseInfoTableDanish(t *testing.T) {
+	table := parseTable(t, danishTable)
+
+	for _, tc := range []struct {
+		labels []string
+		want   string
+	}{
+		// The English label is tried first, then the Danish one.
+		{[]string{"Language of instruction", "Undervisningssprog"}, "Engelsk"},
+		{[]string{"Department", "Institut"}, "01 Institut for Matematik og Computer Science"},
+		{[]string{"Department involved", "Deltagende institut"}, "10 Institut for Fysik"},
+		{[]string{"Aid", "Hjælpemidler"}, "Alle hjælpemidler"},
+		{[]string{"Home page", "Hjemmeside"}, "[Hjemmeside](https://www2.compute.dtu.dk/courses/02105)"},
+		{[]string{"English title", "Engelsk titel"}, "Algorithms and Data Structures 1"},
+	} {
+		if got := table.Value(tc.labels...); got != tc.want {
+			t.Errorf("Value(%q) = %q, want %q", tc.labels, got, tc.want)
+		}
+	}
+
+	// "Institut" must not match "Deltagende institut", nor the other way around.
+	if field, ok := table.Get("Institut"); !ok || field.Label != "Institut" {
+		t.Errorf("Get(Institut) = %+v, %v, want the Institut row", field, ok)
+	}
+}
+
+func TestNormalizeLabel(t *testing.T) {
+	for input, want := range map[string]string{
+		"Schedule":                   "Schedule",
+		"  Schedule:  ":              "Schedule",
+		"Language of\n\tinstruction": "Language of instruction",
+		"Point( ECTS )":              "Point( ECTS )",
+		"":                           "",
+	} {
+		if got := NormalizeLabel(input); got != want {
+			t.Errorf("NormalizeLabel(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
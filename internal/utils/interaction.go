@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DeferResponse acknowledges the interaction straight away, which shows the
+// "Bot is thinking..." state to the user. Discord only gives us 3 seconds to
+// respond to an interaction, but a deferred response can be edited for up to
+// 15 minutes afterwards using EditResponse or SendFollowUp.
+func DeferResponse(s *discordgo.Session, i *discordgo.InteractionCreate, ephemeral bool) error {
+	var flags discordgo.MessageFlags
+	if ephemeral {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: flags,
+		},
+	})
+}
+
+// EditResponse replaces the original (usually deferred) interaction response.
+func EditResponse(s *discordgo.Session, i *discordgo.InteractionCreate, edit *discordgo.WebhookEdit) error {
+	_, err := s.InteractionResponseEdit(i.Interaction, edit)
+	return err
+}
+
+// EditResponseContent replaces the original interaction response with a plain text message.
+func EditResponseContent(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return EditResponse(s, i, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+// SendFollowUp sends an additional message after the interaction has been responded to.
+func SendFollowUp(s *discordgo.Session, i *discordgo.InteractionCreate, params *discordgo.WebhookParams) error {
+	_, err := s.FollowupMessageCreate(i.Interaction, true, params)
+	return err
+}
+
+// ReplaceWithEphemeral removes the deferred "thinking" message and sends the content
+// as an ephemeral follow-up instead, so only the invoking user sees it.
+// This is used for error messages after a public deferred response.
+func ReplaceWithEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string, components ...discordgo.MessageComponent) {
+	if err := s.InteractionResponseDelete(i.Interaction); err != nil {
+		log.Println("Failed to delete deferred response:", err)
+	}
+
+	err := SendFollowUp(s, i, &discordgo.WebhookParams{
+		Content:    content,
+		Components: components,
+		Flags:      discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		log.Println("Failed to send ephemeral follow-up:", err)
+	}
+}
+
+// InteractionUserID returns the ID of the user who triggered the interaction.
+// In guilds the user is on the member, while in DMs it is set directly.
+func InteractionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// OptionMap indexes command options by name.
+func OptionMap(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		m[opt.Name] = opt
+	}
+	return m
+}
+
+// FocusedOption returns the option the user is currently typing in during autocomplete,
+// looking inside subcommands as well.
+func FocusedOption(options []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range options {
+		if opt.Focused {
+			return opt
+		}
+		if opt.Type == discordgo.ApplicationCommandOptionSubCommand || opt.Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+			if focused := FocusedOption(opt.Options); focused != nil {
+				return focused
+			}
+		}
+	}
+	return nil
+}
@@ -169,12 +169,16 @@ func MakePaginationEmbed(data *PaginationData) *discordgo.MessageEmbed {
 	}
 
 	pageCount := data.GetPageAmount()
+	footer := fmt.Sprintf("Page: %d / %d", data.PageIndex+1, pageCount)
+	if data.Footer != "" {
+		footer = data.Footer + " | " + footer
+	}
 	return &discordgo.MessageEmbed{
 		Title:       data.Title,
 		Description: data.Description,
 		Color:       data.Color,
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Page: %d / %d", data.PageIndex+1, pageCount),
+			Text: footer,
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 		Fields:    fields,
@@ -210,3 +214,28 @@ func SendInitialPaginationResponse(
 
 	return err
 }
+
+// EditPaginationResponse is the deferred counterpart of SendInitialPaginationResponse.
+// It replaces the "thinking" message of a deferred interaction with the first page.
+// Any files are attached to the message and stay there while paging.
+func EditPaginationResponse(
+	s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	paginationID string,
+	data *PaginationData,
+	files ...*discordgo.File,
+) error {
+	embeds := []*discordgo.MessageEmbed{MakePaginationEmbed(data)}
+	components := MakePaginationComponents(paginationID, data.PageIndex, data.GetPageAmount())
+
+	err := EditResponse(s, i, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+		Files:      files,
+	})
+	if err != nil {
+		log.Println("Failed to edit response with paginated embed:", err)
+	}
+
+	return err
+}
@@ -3,8 +3,12 @@ package utils
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
+// MaxFieldValueLength is the longest value Discord accepts for an embed field.
+const MaxFieldValueLength = 1024
+
 type Section interface {
 	SetInLine(IsInLine bool)
 	GetSectionName() string
@@ -44,3 +48,17 @@ func WriteTitle(title string) string {
 	}
 	return fmt.Sprintf("**%s**\n", title)
 }
+
+// Truncate shortens s to at most maxLen bytes, ending it with "…" if anything was cut.
+// It never splits a multi-byte character.
+func Truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	const ellipsis = "…"
+	cut := maxLen - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return strings.TrimSpace(s[:cut]) + ellipsis
+}
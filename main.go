@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Chrisser1/Discord-Bot-DTU/internal/model"
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/utils"
 
 	"github.com/Chrisser1/Discord-Bot-DTU/internal/config"
@@ -31,6 +35,12 @@ func main() {
 	// Load the configuration
 	config.LoadConfig()
 
+	// "go run . crawl" crawls the course catalogue once instead of starting the bot.
+	if flag.Arg(0) == "crawl" {
+		os.Exit(crawl())
+	}
+	config.GlobalConfig.RequireBotSettings()
+
 	// Create pagination manager with a chosen TTL, e.g. 5 minutes.
 	paginationManager := utils.NewPaginatedSessions(5 * time.Minute)
 
@@ -50,7 +60,7 @@ func main() {
 
 	// Wait for a signal to gracefully shut down the bot
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	log.Println("Press Ctrl+C to exit")
 	<-stop
 
@@ -60,9 +70,40 @@ func main() {
 	}
 	// When shutting down gracefully, stop the pagination manager’s GC loop
 	paginationManager.Stop()
+	// Close the shared browser and the Discord session
+	discordSvc.Close()
 	log.Println("Gracefully shutting down.")
 }
 
+// crawl fetches every course in the catalogue and records it in the course store, and returns
+// the exit code. It stops early, keeping what it has fetched so far, on Ctrl+C.
+// It returns instead of exiting, so the browser is closed also when the crawl fails.
+func crawl() int {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	browserPool := utils.NewBrowserPool(config.GlobalConfig.BrowserTabs, utils.BrowserOptions{
+		RemoteURL: config.GlobalConfig.ChromeRemoteURL,
+		ExecPath:  config.GlobalConfig.ChromePath,
+		Flags:     config.GlobalConfig.ChromeFlags,
+		UserAgent: config.GlobalConfig.ChromeUserAgent,
+	})
+	defer browserPool.Close()
+	model.SetCourseSource(model.NewConfiguredCourseSource(browserPool))
+
+	result, err := model.RunCrawl(ctx)
+	log.Printf("Crawled %d of %d courses in %s, %d failed", result.Fetched, result.Listed, result.Duration.Round(time.Second), len(result.Failed))
+	if len(result.Failed) > 0 {
+		log.Printf("Failed courses: %s", strings.Join(result.Failed, ", "))
+	}
+	log.Println("Course fetches:", model.GetFetchMetrics())
+	if err != nil {
+		log.Printf("Crawl failed: %v", err)
+		return 1
+	}
+	return 0
+}
+
 // import (
 // 	"fmt"
 // 	"log"